@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+)
+
+// replacementAlgos holds every supported replacement algorithm.
+var replacementAlgos = []ReplacementAlgo{LRU_ALGO, MRU_ALGO, LFU_ALGO, FIFO_ALGO, RANDOM_ALGO}
+
+// ParseReplacementAlgo returns the replacement algorithm matching the provided name, ignoring case,
+// e.g. "lru", "Lru" and "LRU" all return LRU_ALGO. It returns an error wrapping ErrUnknownReplacementAlgo
+// if the name doesn't match any supported algorithm.
+func ParseReplacementAlgo(name string) (ReplacementAlgo, error) {
+	for _, algo := range replacementAlgos {
+		if strings.EqualFold(name, string(algo)) {
+			return algo, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownReplacementAlgo, name)
+}
+
+// isValid returns true if the algorithm is exactly one of the supported ones.
+func (a ReplacementAlgo) isValid() bool {
+	for _, algo := range replacementAlgos {
+		if a == algo {
+			return true
+		}
+	}
+	return false
+}
+
+// isInsertionOrdered returns true if the algorithm ignores reads, so the entries of a set are kept
+// in insertion order instead of being moved to the front on every hit.
+func (a ReplacementAlgo) isInsertionOrdered() bool {
+	return a == FIFO_ALGO || a == RANDOM_ALGO
+}
+
+// MarshalText implements encoding.TextMarshaler. The empty algorithm, meaning the default one,
+// is marshalled as an empty text. It returns an error wrapping ErrUnknownReplacementAlgo
+// if the algorithm is not supported.
+func (a ReplacementAlgo) MarshalText() ([]byte, error) {
+	if a != "" && !a.isValid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownReplacementAlgo, string(a))
+	}
+	return []byte(a), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so the algorithm can be loaded directly from
+// JSON, YAML or any other text based configuration. Names are matched ignoring case, see ParseReplacementAlgo.
+// An empty text is unmarshalled as the empty algorithm, so the constructors use the default one.
+func (a *ReplacementAlgo) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = ""
+		return nil
+	}
+	algo, err := ParseReplacementAlgo(string(text))
+	if err != nil {
+		return err
+	}
+	*a = algo
+	return nil
+}
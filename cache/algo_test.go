@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing replacement algorithms parsing", parseReplacementAlgoTest)
+
+func parseReplacementAlgoTest() {
+	Context("Given ParseReplacementAlgo", func() {
+		It("should match every supported algorithm ignoring case", func() {
+			for name, expected := range map[string]ReplacementAlgo{
+				"LRU":    LRU_ALGO,
+				"lru":    LRU_ALGO,
+				"Mru":    MRU_ALGO,
+				"lfu":    LFU_ALGO,
+				"fifo":   FIFO_ALGO,
+				"Random": RANDOM_ALGO,
+			} {
+				algo, err := ParseReplacementAlgo(name)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(algo).Should(Equal(expected))
+			}
+		})
+
+		It("should return ErrUnknownReplacementAlgo for an unknown name", func() {
+			_, err := ParseReplacementAlgo("LRU2")
+			Expect(errors.Is(err, ErrUnknownReplacementAlgo)).Should(BeTrue())
+			Expect(err.Error()).Should(ContainSubstring(`"LRU2"`))
+		})
+	})
+
+	Context("Given NewCache with an unknown replacement algorithm", func() {
+		It("should return ErrUnknownReplacementAlgo instead of falling back to LRU", func() {
+			for _, algo := range []ReplacementAlgo{"mru", "ARC"} {
+				cache, err := NewCache[int, string](4, algo)
+				Expect(cache).Should(BeNil())
+				Expect(errors.Is(err, ErrUnknownReplacementAlgo)).Should(BeTrue())
+			}
+		})
+	})
+
+	Context("Given a ReplacementAlgo in a JSON configuration", func() {
+		type config struct {
+			Algo ReplacementAlgo `json:"algo"`
+		}
+
+		It("should unmarshal it ignoring case", func() {
+			var cfg config
+			Expect(json.Unmarshal([]byte(`{"algo":"mru"}`), &cfg)).Should(Succeed())
+			Expect(cfg.Algo).Should(Equal(MRU_ALGO))
+
+			cache, err := NewCache[int, string](4, cfg.Algo)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache).ShouldNot(BeNil())
+		})
+
+		It("should return ErrUnknownReplacementAlgo when unmarshalling an unknown algorithm", func() {
+			var cfg config
+			err := json.Unmarshal([]byte(`{"algo":"FIFO2"}`), &cfg)
+			Expect(errors.Is(err, ErrUnknownReplacementAlgo)).Should(BeTrue())
+		})
+
+		It("should marshal it back to its name", func() {
+			data, err := json.Marshal(config{Algo: LFU_ALGO})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).Should(Equal(`{"algo":"LFU"}`))
+		})
+
+		It("should marshal an empty algorithm to an empty text and load it back as LRU", func() {
+			data, err := json.Marshal(config{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).Should(Equal(`{"algo":""}`))
+
+			var cfg config
+			Expect(json.Unmarshal(data, &cfg)).Should(Succeed())
+			Expect(cfg.Algo).Should(BeEmpty())
+
+			cache, err := NewCache[int, string](4, cfg.Algo)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache.newPolicy()).Should(Equal(NewLRUPolicy[int]()))
+		})
+
+		It("should configure LRU for an omitted algorithm", func() {
+			var cfg config
+			Expect(json.Unmarshal([]byte(`{}`), &cfg)).Should(Succeed())
+
+			cache, err := NewCacheWithOptions[int, string](WithSets(4), WithWays(4), WithReplacementAlgo(cfg.Algo))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache.newPolicy()).Should(Equal(NewLRUPolicy[int]()))
+		})
+
+		It("should return ErrUnknownReplacementAlgo when marshalling an unknown algorithm", func() {
+			_, err := json.Marshal(config{Algo: "FIFO2"})
+			Expect(errors.Is(err, ErrUnknownReplacementAlgo)).Should(BeTrue())
+		})
+	})
+}
@@ -3,23 +3,46 @@ package cache
 import (
 	"container/list"
 	"fmt"
-	"hash/fnv"
+	"math/rand/v2"
+	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Cache structure to be used for handling the cache data
+// Cache structure to be used for handling the cache data.
+// Every set is locked independently, so operations on keys mapped to different sets run in parallel.
 type Cache[K comparable, V any] struct {
 	setSize               int
-	sets                  map[int]*list.List
-	entries               map[K]*list.Element
+	ways                  int
+	sets                  []cacheSet[K, V]
 	hashKeyToIntConverter hashKeyToIntConverter[K]
-	getItemToRemove       func(currentSet *list.List) *list.Element
-	mutex                 sync.Mutex
+	newPolicy             func() EvictionPolicy[K]
+	insertionOrdered      bool
+	defaultTTL            time.Duration
+	now                   func() time.Time
+	janitor               *janitor
+	onEvict               func(key K, value V, reason EvictionReason)
+	loads                 map[K]*loadCall[V]
+	loadMutex             sync.Mutex
+	length                atomic.Int64
+	stats                 cacheStats
+	tags                  tagIndex[K]
+	setStats              []setCounters
+	missClassifier        *missClassifier[K]
 }
 
 type entry[K comparable, V any] struct {
-	key   K
-	value V
+	key       K
+	value     V
+	expiresAt time.Time
+	tags      []string
+}
+
+// isExpired returns true if the entry has an expiration time and it's not after now.
+func (e *entry[K, V]) isExpired(now func() time.Time) bool {
+	return !e.expiresAt.IsZero() && !e.expiresAt.After(now())
 }
 
 type ReplacementAlgo string
@@ -27,138 +50,541 @@ type ReplacementAlgo string
 const (
 	LRU_ALGO ReplacementAlgo = "LRU"
 	MRU_ALGO ReplacementAlgo = "MRU"
+	LFU_ALGO ReplacementAlgo = "LFU"
+	// FIFO_ALGO evicts the oldest inserted entry of the set. Hits don't change the order of the set,
+	// so Get only takes the shared lock of the set.
+	FIFO_ALGO ReplacementAlgo = "FIFO"
+	// RANDOM_ALGO evicts a uniformly random entry of the set, see WithRand to inject the random generator.
+	// As FIFO_ALGO, Get only takes the shared lock of the set.
+	RANDOM_ALGO ReplacementAlgo = "RANDOM"
 )
 
+// ReplacementPolicy is the replacement strategy accepted by NewCache:
+// either a ReplacementAlgo or a PolicyConstructor of a custom EvictionPolicy.
+type ReplacementPolicy interface {
+	option() Option
+}
+
+// option returns the Option configuring the algorithm.
+func (a ReplacementAlgo) option() Option {
+	return WithReplacementAlgo(a)
+}
+
 var (
+	// Deprecated: the cache no longer uses it, the replacement order is kept by the EvictionPolicy of every set.
+	// Use LRU_ALGO, or NewLRUPolicy together with WithEvictionPolicy.
 	LRU_ITEM_TO_REMOVE_GETTER = func(currentSet *list.List) *list.Element {
 		return currentSet.Back()
 	}
 
+	// Deprecated: the cache no longer uses it, the replacement order is kept by the EvictionPolicy of every set.
+	// Use MRU_ALGO, or NewMRUPolicy together with WithEvictionPolicy.
 	MRU_ITEM_TO_REMOVE_GETTER = func(currentSet *list.List) *list.Element {
 		return currentSet.Front()
 	}
 )
 
 // NewCache returns a new instance of Cache. It saves the provided setSize in the returned instance.
-// If replacementAlgorithm is not provided the cache configures LRU by default.
+// The provided setSize is used both as the number of sets and as the number of ways per set,
+// so the resulting cache holds setSize*setSize entries. Use NewCacheWithGeometry to configure them independently.
+// If replacementPolicy is not provided, or it's an empty algorithm, the cache configures LRU by default.
 // It allows you to define a specific strategy for refreshing cached data, as well.
 // To define the MRU strategy, initialize the cache as follows:
 //   - cache.NewCache[int, any](5, cache.MRU_ALGO)
 //
 // To explicitly define LRU, you can use:
 //   - cache.NewCache[int, any](5, cache.LRU_ALGO)
-func NewCache[K comparable, V any](setSize int, replacementAlgorithm ...ReplacementAlgo) (*Cache[K, V], error) {
+//
+// To define the LFU strategy, you can use:
+//   - cache.NewCache[int, any](5, cache.LFU_ALGO)
+//
+// FIFO_ALGO and RANDOM_ALGO are available as well.
+//
+// To define a custom EvictionPolicy, provide its constructor as a PolicyConstructor:
+//   - cache.NewCache[int, any](5, cache.PolicyConstructor[int](NewMyPolicy))
+//
+// At most one replacementPolicy can be provided.
+// Use NewCacheWithOptions for any other setting.
+func NewCache[K comparable, V any](setSize int, replacementPolicy ...ReplacementPolicy) (*Cache[K, V], error) {
 	if setSize <= 0 {
-		return nil, fmt.Errorf("setSize provided '%d', must be a positive value", setSize)
+		return nil, &ConfigError{Setting: "setSize", Value: setSize, Reason: "must be a positive value", Err: ErrInvalidSetSize}
+	}
+
+	if len(replacementPolicy) > 1 {
+		return nil, &ConfigError{Setting: "replacementPolicy", Value: replacementPolicy, Reason: "only one replacement policy can be provided", Err: ErrConflictingOptions}
+	}
+
+	opts := []Option{WithSets(setSize), WithWays(setSize)}
+	if len(replacementPolicy) == 1 {
+		opts = append(opts, replacementPolicy[0].option())
+	}
+
+	return NewCacheWithOptions[K, V](opts...)
+}
+
+// NewCacheWithGeometry returns a new instance of Cache with numSets sets of ways entries each,
+// so the total capacity of the cache is numSets*ways (see Cap).
+// Both values must be positive. Additional behaviour is configured through options, e.g.:
+//   - cache.NewCacheWithGeometry[int, any](1024, 8, cache.WithReplacementAlgo(cache.MRU_ALGO))
+//
+// It's equivalent to NewCacheWithOptions with WithSets(numSets) and WithWays(ways), which take precedence
+// over any WithSets or WithWays provided in opts.
+func NewCacheWithGeometry[K comparable, V any](numSets, ways int, opts ...Option) (*Cache[K, V], error) {
+	opts = append(slices.Clip(opts), WithSets(numSets), WithWays(ways))
+	return NewCacheWithOptions[K, V](opts...)
+}
+
+// NewCacheWithOptions returns a new instance of Cache configured through the provided options.
+// WithSets and WithWays are required, the rest of the options are optional, e.g.:
+//   - cache.NewCacheWithOptions[int, any](cache.WithSets(1024), cache.WithWays(8), cache.WithDefaultTTL(time.Minute))
+//
+// Every setting is validated and a *ConfigError describing the first invalid one is returned, if any.
+// The ConfigError wraps a sentinel error, e.g. ErrInvalidSetSize, which can be checked with errors.Is.
+func NewCacheWithOptions[K comparable, V any](opts ...Option) (*Cache[K, V], error) {
+	cfg := newConfig(opts...)
+
+	if cfg.numSets <= 0 {
+		return nil, &ConfigError{Setting: "numSets", Value: cfg.numSets, Reason: "must be a positive value", Err: ErrInvalidSetSize}
+	}
+
+	if cfg.ways <= 0 {
+		return nil, &ConfigError{Setting: "ways", Value: cfg.ways, Reason: "must be a positive value", Err: ErrInvalidWays}
+	}
+
+	if cfg.replacementAlgorithm == "" {
+		cfg.replacementAlgorithm = LRU_ALGO
+	}
+	if !cfg.replacementAlgorithm.isValid() {
+		return nil, &ConfigError{
+			Setting: "replacementAlgorithm",
+			Value:   cfg.replacementAlgorithm,
+			Reason:  "is not a supported replacement algorithm, use ParseReplacementAlgo to load it from text",
+			Err:     ErrUnknownReplacementAlgo,
+		}
+	}
+
+	var converter hashKeyToIntConverter[K]
+	if cfg.hasher != nil {
+		if cfg.hashSeed != nil || cfg.randomHashSeed {
+			return nil, &ConfigError{Setting: "hash seed", Reason: "can't be combined with a custom hasher", Err: ErrConflictingOptions}
+		}
+		hasher, ok := cfg.hasher.(Hasher[K])
+		if !ok {
+			return nil, &ConfigError{Setting: "hasher", Value: reflect.TypeOf(cfg.hasher), Reason: fmt.Sprintf("doesn't match the key data type %v", reflect.TypeFor[K]()), Err: ErrTypeMismatch}
+		}
+		converter = &hasherConverter[K]{hasher: hasher}
+	} else if !isPrimitiveDataType[K]() {
+		return nil, &ConfigError{Setting: "key data type", Value: reflect.TypeFor[K](), Reason: "is not a supported primitive data type, provide a custom hasher with WithHasher", Err: ErrUnsupportedKeyType}
+	} else {
+		hashImpl, err := newHashKeyToIntImpl[K](cfg)
+		if err != nil {
+			return nil, err
+		}
+		converter = hashImpl
+	}
+
+	if cfg.defaultTTL < 0 {
+		return nil, &ConfigError{Setting: "defaultTTL", Value: cfg.defaultTTL, Reason: "must not be a negative value", Err: ErrInvalidTTL}
+	}
+
+	if cfg.readBufferSize < 0 {
+		return nil, &ConfigError{Setting: "readBufferSize", Value: cfg.readBufferSize, Reason: "must be a positive value", Err: ErrInvalidReadBufferSize}
+	}
+
+	if cfg.janitorInterval != 0 || cfg.janitorSetsPerPass != 0 {
+		if cfg.janitorInterval <= 0 {
+			return nil, &ConfigError{Setting: "janitorInterval", Value: cfg.janitorInterval, Reason: "must be a positive value", Err: ErrInvalidJanitor}
+		}
+		if cfg.janitorSetsPerPass <= 0 {
+			return nil, &ConfigError{Setting: "janitorSetsPerPass", Value: cfg.janitorSetsPerPass, Reason: "must be a positive value", Err: ErrInvalidJanitor}
+		}
+	}
+
+	intN := rand.IntN
+	if cfg.rand != nil {
+		intN = (&lockedRand{rng: cfg.rand}).IntN
+	}
+	newPolicy := policyFactory[K](cfg.replacementAlgorithm, intN)
+	insertionOrdered := cfg.replacementAlgorithm.isInsertionOrdered()
+	if cfg.evictionPolicy != nil {
+		customPolicy, ok := cfg.evictionPolicy.(func() EvictionPolicy[K])
+		if !ok {
+			return nil, &ConfigError{Setting: "evictionPolicy", Value: reflect.TypeOf(cfg.evictionPolicy), Reason: fmt.Sprintf("doesn't match the key data type %v", reflect.TypeFor[K]()), Err: ErrTypeMismatch}
+		}
+		newPolicy = customPolicy
+		insertionOrdered = false
+	}
+
+	var onEvict func(key K, value V, reason EvictionReason)
+	if cfg.onEvict != nil {
+		listener, ok := cfg.onEvict.(func(key K, value V, reason EvictionReason))
+		if !ok {
+			return nil, &ConfigError{Setting: "onEvict", Value: reflect.TypeOf(cfg.onEvict), Reason: fmt.Sprintf("doesn't match the key and value data types %v and %v", reflect.TypeFor[K](), reflect.TypeFor[V]()), Err: ErrTypeMismatch}
+		}
+		onEvict = listener
 	}
 
-	var zero K
-	if !isPrimitiveDataType(zero) {
-		return nil, fmt.Errorf("provided data type for key is not a supported primitive data type, data type received: %T", zero)
+	cache := &Cache[K, V]{
+		setSize:               cfg.numSets,
+		ways:                  cfg.ways,
+		sets:                  make([]cacheSet[K, V], cfg.numSets),
+		hashKeyToIntConverter: converter,
+		newPolicy:             newPolicy,
+		insertionOrdered:      insertionOrdered,
+		defaultTTL:            cfg.defaultTTL,
+		now:                   cfg.clock,
+		onEvict:               onEvict,
+		setStats:              make([]setCounters, cfg.numSets),
 	}
 
-	getItemToRemove := LRU_ITEM_TO_REMOVE_GETTER
-	if replacementAlgorithm != nil && replacementAlgorithm[0] == MRU_ALGO {
-		getItemToRemove = MRU_ITEM_TO_REMOVE_GETTER
+	if cfg.readBufferSize > 0 && !insertionOrdered {
+		for i := range cache.sets {
+			cache.sets[i].reads = newReadStripes(cfg.readBufferSize)
+		}
+	}
+
+	if cfg.missClassification {
+		cache.missClassifier = newMissClassifier[K](cache.Cap())
 	}
 
-	return &Cache[K, V]{
-		setSize:               setSize,
-		sets:                  make(map[int]*list.List),
-		entries:               make(map[K]*list.Element),
-		hashKeyToIntConverter: new(hashKeyToIntImpl[K]),
-		getItemToRemove:       getItemToRemove,
-	}, nil
+	if cfg.janitorInterval > 0 {
+		cache.startJanitor(cfg.janitorInterval, cfg.janitorSetsPerPass)
+	}
+
+	return cache, nil
+}
+
+// Cap returns the maximum number of entries the cache can hold, that is number of sets * ways per set.
+func (c *Cache[K, V]) Cap() int {
+	return c.setSize * c.ways
+}
+
+// Len returns the number of entries in the cache, expired entries not removed yet included.
+// It doesn't lock the cache, so it's cheap enough to be polled by metrics.
+func (c *Cache[K, V]) Len() int {
+	return int(c.length.Load())
+}
+
+// SetOccupancy returns the number of entries of every set, indexed by set,
+// expired entries not removed yet included. Every value is between 0 and the number of ways.
+// Sets are locked one at a time, so the result is consistent per set.
+func (c *Cache[K, V]) SetOccupancy() []int {
+	result := make([]int, len(c.sets))
+	for i := range c.sets {
+		set := &c.sets[i]
+		set.mutex.RLock()
+		result[i] = set.len()
+		set.mutex.RUnlock()
+	}
+	return result
 }
 
 // Put implements functionality that seet a new value in the cache, following n-way-set-associative-cache
+// The entry expires after the default TTL defined with WithDefaultTTL, if any.
+// Saving a key again keeps the tags it was saved with by PutWithTags.
 func (c *Cache[K, V]) Put(key K, value V) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	c.PutWithTTL(key, value, c.defaultTTL)
+}
 
-	setIndex := c.hashKeyToIntConverter.hashKeyToInt(key) % c.setSize
-	if elem, found := c.entries[key]; found {
-		c.sets[setIndex].MoveToFront(elem)
-		elem.Value.(*entry[K, V]).value = value
-		return
+// PutWithTTL works as Put, but the entry expires once the provided ttl has elapsed.
+// Expired entries are treated as missing. A ttl <= 0 means the entry never expires.
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	setIndex, set := c.setOf(key)
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	c.put(setIndex, key, value, c.expiresAt(ttl), nil)
+}
+
+// expiresAt returns the expiration time of an entry saved now with the provided ttl,
+// or the zero time if the entry never expires.
+func (c *Cache[K, V]) expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
 	}
+	return c.now().Add(ttl)
+}
 
-	if c.sets[setIndex] == nil {
-		c.sets[setIndex] = list.New()
+// put saves the key-value pair in the provided set with the provided tags, replacing the previous ones,
+// or keeping them if tags is nil, and evicts the victim of the set policy if it's full. An expired previous entry is removed as expired
+// and the key is inserted again, since its value wasn't replaced while it was alive.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) put(setIndex int, key K, value V, expiresAt time.Time, tags []string) {
+	set := &c.sets[setIndex]
+	set.init(c.newPolicy)
+	set.applyReads()
+	if elem, found := set.entries[key]; found && elem.Value.(*entry[K, V]).isExpired(c.now) {
+		c.remove(setIndex, key, EvictionReasonExpired)
+	} else if found {
+		if !c.insertionOrdered {
+			set.items.MoveToFront(elem)
+		}
+		cachedEntry := elem.Value.(*entry[K, V])
+		c.recordEviction(set, key, cachedEntry.value, EvictionReasonReplaced)
+		cachedEntry.value = value
+		cachedEntry.expiresAt = expiresAt
+		if tags != nil {
+			c.tags.remove(key, cachedEntry.tags)
+			c.tags.add(key, tags)
+			cachedEntry.tags = tags
+		}
+		set.policy.OnUpdate(key)
+		c.recordReference(key)
+		return
 	}
 
-	if c.sets[setIndex].Len() >= c.setSize {
-		elementToRemove := c.getItemToRemove(c.sets[setIndex])
-		if elementToRemove != nil {
-			delete(c.entries, elementToRemove.Value.(*entry[K, V]).key)
-			c.sets[setIndex].Remove(elementToRemove)
+	if set.items.Len() >= c.ways {
+		keyToRemove, ok := set.policy.Victim()
+		if !ok || !c.remove(setIndex, keyToRemove, EvictionReasonCapacity) {
+			// a custom policy without a victim of the set must not let the set grow past its ways,
+			// so the least recently saved entry is evicted instead
+			c.remove(setIndex, set.items.Back().Value.(*entry[K, V]).key, EvictionReasonCapacity)
 		}
 	}
 
-	newEntry := &entry[K, V]{key: key, value: value}
-	elem := c.sets[setIndex].PushFront(newEntry)
-	c.entries[key] = elem
+	newEntry := &entry[K, V]{key: key, value: value, expiresAt: expiresAt, tags: tags}
+	set.entries[key] = set.items.PushFront(newEntry)
+	c.tags.add(key, tags)
+	set.policy.OnInsert(key)
+	c.length.Add(1)
+	c.stats.insertions.Add(1)
+	c.recordReference(key)
 }
 
 // Get returns the item if it's present in cache and a true flag.
-// Otherwise it returns false and an empty value
+// Otherwise it returns false and an empty value.
+// An expired item is removed from the cache and reported as missing.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	setIndex, set := c.setOf(key)
+	if c.insertionOrdered || set.reads != nil {
+		if value, found, done := c.getShared(setIndex, set, key); done {
+			return value, found
+		}
+	}
+
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	return c.get(setIndex, key)
+}
+
+// get looks the key up in the provided set, promoting it on a hit and removing it if it's expired.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) get(setIndex int, key K) (V, bool) {
+	set := &c.sets[setIndex]
+	set.applyReads()
+	if elem, found := set.entries[key]; found {
+		if elem.Value.(*entry[K, V]).isExpired(c.now) {
+			c.remove(setIndex, key, EvictionReasonExpired)
+			c.recordMiss(setIndex, key)
+			var zero V
+			return zero, false
+		}
 
-	if elem, found := c.entries[key]; found {
-		setIndex := c.hashKeyToIntConverter.hashKeyToInt(key) % c.setSize
-		c.sets[setIndex].MoveToFront(elem)
+		if !c.insertionOrdered {
+			set.items.MoveToFront(elem)
+		}
+		set.policy.OnAccess(key)
+		c.recordHit(setIndex, key)
 		return elem.Value.(*entry[K, V]).value, true
 	}
+	c.recordMiss(setIndex, key)
 	var zero V
 	return zero, false
 }
 
-// ListAll returns all element saved in cache
-func (c *Cache[K, V]) ListAll() map[K]V {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+// getShared looks the key up under the shared lock of the set. A hit is recorded in the read buffer,
+// if any, instead of reordering the set. It returns done false if the entry is expired,
+// since removing it requires the exclusive lock.
+func (c *Cache[K, V]) getShared(setIndex int, set *cacheSet[K, V], key K) (value V, found bool, done bool) {
+	set.mutex.RLock()
+	elem, found := set.entries[key]
+	if !found {
+		c.recordMiss(setIndex, key)
+		set.mutex.RUnlock()
+		return value, false, true
+	}
+
+	cachedEntry := elem.Value.(*entry[K, V])
+	if cachedEntry.isExpired(c.now) {
+		set.mutex.RUnlock()
+		return value, false, false
+	}
+	value = cachedEntry.value
+	c.recordHit(setIndex, key)
+	full := set.reads != nil && set.recordRead(elem)
+	set.mutex.RUnlock()
+
+	// the stripe is full: apply the buffer unless another goroutine holds the lock,
+	// in that case the next reads recorded in the stripe are dropped until it's applied
+	if full && set.mutex.TryLock() {
+		set.applyReads()
+		set.mutex.Unlock()
+	}
+	return value, true, true
+}
+
+// Peek returns the item if it's present in cache and a true flag, as Get does,
+// but it doesn't change the replacement order of the set nor the stats.
+// An expired item is reported as missing, but it's not removed.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	_, set := c.setOf(key)
+	set.mutex.RLock()
+	defer set.mutex.RUnlock()
+
+	if elem, found := set.entries[key]; found {
+		if cachedEntry := elem.Value.(*entry[K, V]); !cachedEntry.isExpired(c.now) {
+			return cachedEntry.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Contains returns true if the key is present in cache and it's not expired.
+// As Peek, it doesn't change the replacement order of the set nor the stats.
+func (c *Cache[K, V]) Contains(key K) bool {
+	_, found := c.Peek(key)
+	return found
+}
 
+// ListAll returns all element saved in cache, expired elements are not included.
+// Sets are locked one at a time, so the result is consistent per set but writes to other sets
+// may happen while it's being built.
+func (c *Cache[K, V]) ListAll() map[K]V {
 	result := make(map[K]V)
-	for k, elem := range c.entries {
-		result[k] = elem.Value.(*entry[K, V]).value
+	for i := range c.sets {
+		set := &c.sets[i]
+		set.mutex.Lock()
+		for k, elem := range set.entries {
+			cachedEntry := elem.Value.(*entry[K, V])
+			if cachedEntry.isExpired(c.now) {
+				continue
+			}
+			result[k] = cachedEntry.value
+		}
+		set.mutex.Unlock()
 	}
 	return result
 }
 
 // Delete removes the item associated to the provided key if it's found.
 func (c *Cache[K, V]) Delete(key K) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	setIndex, set := c.setOf(key)
+	set.mutex.Lock()
+	defer c.unlock(set)
 
-	if elem, found := c.entries[key]; found {
-		setIndex := c.hashKeyToIntConverter.hashKeyToInt(key) % c.setSize
-		c.sets[setIndex].Remove(elem)
-		delete(c.entries, key)
+	c.remove(setIndex, key, EvictionReasonDeleted)
+}
+
+// DeleteFunc removes every entry for which the provided predicate returns true and returns how many were removed.
+// Expired entries are not passed to the predicate. Sets are processed one at a time, holding only the lock of
+// the current set, so the cache is never locked for longer than scanning a single set, and writes to other sets
+// may happen while it's running. The predicate is invoked while holding that lock, so it must not use the cache.
+func (c *Cache[K, V]) DeleteFunc(predicate func(key K, value V) bool) int {
+	removed := 0
+	for setIndex := range c.sets {
+		removed += c.deleteFromSet(setIndex, predicate)
 	}
+	return removed
 }
 
-// isPrimitiveDataType returns true if the input data type is int, float32, float64, bool or string
-func isPrimitiveDataType[K any](input K) bool {
-	switch any(input).(type) {
-	case int, float32, float64, bool, string:
-		return true
+// deleteFromSet removes the entries of the provided set for which the predicate returns true
+// and returns how many were removed.
+func (c *Cache[K, V]) deleteFromSet(setIndex int, predicate func(key K, value V) bool) int {
+	set := &c.sets[setIndex]
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	if set.items == nil {
+		return 0
 	}
 
-	return false
+	removed := 0
+	for elem := set.items.Front(); elem != nil; {
+		next := elem.Next()
+		cachedEntry := elem.Value.(*entry[K, V])
+		if !cachedEntry.isExpired(c.now) && predicate(cachedEntry.key, cachedEntry.value) {
+			c.remove(setIndex, cachedEntry.key, EvictionReasonDeleted)
+			removed++
+		}
+		elem = next
+	}
+	return removed
+}
+
+// Clear removes every entry of the cache.
+// It's atomic: every set is locked in index order and its entries are swapped out, so no reader can
+// observe a partially cleared cache. The removed entries are notified to the eviction listener
+// once every lock has been released.
+func (c *Cache[K, V]) Clear() {
+	cleared := make([]*list.List, 0, len(c.sets))
+	for i := range c.sets {
+		c.sets[i].mutex.Lock()
+	}
+	if c.missClassifier != nil {
+		c.missClassifier.reset()
+	}
+	c.tags.reset()
+	for i := range c.sets {
+		set := &c.sets[i]
+		if set.items != nil {
+			cleared = append(cleared, set.items)
+			c.length.Add(-int64(set.items.Len()))
+		}
+		set.reset()
+	}
+	for i := range c.sets {
+		c.sets[i].mutex.Unlock()
+	}
+
+	for _, items := range cleared {
+		for elem := items.Front(); elem != nil; elem = elem.Next() {
+			cachedEntry := elem.Value.(*entry[K, V])
+			c.stats.recordRemoval(EvictionReasonCleared)
+			if c.onEvict != nil {
+				c.onEvict(cachedEntry.key, cachedEntry.value, EvictionReasonCleared)
+			}
+		}
+	}
 }
 
-type hashKeyToIntConverter[K comparable] interface {
-	hashKeyToInt(key K) int
+// remove deletes the provided key from its set and notifies the set policy
+// and the eviction listener with the provided reason. It returns false if the key wasn't found.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) remove(setIndex int, key K, reason EvictionReason) bool {
+	set := &c.sets[setIndex]
+	elem, found := set.entries[key]
+	if !found {
+		return false
+	}
+
+	cachedEntry := elem.Value.(*entry[K, V])
+	set.items.Remove(elem)
+	delete(set.entries, key)
+	c.length.Add(-1)
+	set.policy.OnRemove(key)
+	c.tags.remove(key, cachedEntry.tags)
+	c.recordEviction(set, key, cachedEntry.value, reason)
+	if reason == EvictionReasonCapacity {
+		c.setStats[setIndex].evictions.Add(1)
+	} else {
+		c.forgetReference(key)
+	}
+	return true
 }
 
-type hashKeyToIntImpl[K comparable] struct{}
+// isPrimitiveDataType returns true if the kind of the K data type is bool, string, any integer or unsigned integer,
+// float or complex. Named types are supported as long as their underlying type is one of them, e.g. type UserID int64.
+func isPrimitiveDataType[K any]() bool {
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		return true
+	}
 
-// hashKeyToInt converts a given key of any comparable type into a hashed integer value.
-// For this it get the string equivalent to concatenation of the key value and data type.
-func (*hashKeyToIntImpl[K]) hashKeyToInt(key K) int {
-	hasher := fnv.New32a()
-	hasher.Write([]byte(fmt.Sprintf("%[1]v%[1]T", key)))
-	return int(hasher.Sum32())
+	return false
 }
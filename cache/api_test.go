@@ -1,10 +1,13 @@
 package cache
 
 import (
-	"container/list"
+	"errors"
+	"fmt"
 	"math"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -18,6 +21,7 @@ func TestMainSuite(t *testing.T) {
 
 var _ = Describe("testing api functionality", func() {
 	Describe("testing function NewCache", newCacheTest)
+	Describe("testing function NewCacheWithOptions", newCacheWithOptionsTest)
 	Describe("testing function Put", putTest)
 	Describe("testing function Get", getTest)
 	Describe("testing function ListAll", listAllTest)
@@ -43,7 +47,7 @@ func newCacheTest() {
 			- A not nil instance
 			- Instance fields must not be empty
 			- setSize = 2 (provided as input)
-			- Not nil function getItemToRemove (MRU scenario)
+			- Not nil function newPolicy (MRU scenario)
 			- Nil error`, func() {
 			cache, err := NewCache[int, string](2, MRU_ALGO)
 			Expect(err).Error().ShouldNot(HaveOccurred())
@@ -52,14 +56,28 @@ func newCacheTest() {
 			Expect(cache.sets).ShouldNot(BeNil())
 			Expect(cache.setSize).Should(Equal(2))
 			Expect(cache.setSize).ShouldNot(BeNil())
-			Expect(cache.getItemToRemove).ShouldNot(BeNil())
+			Expect(cache.newPolicy).ShouldNot(BeNil())
+		})
+
+		It(`should return:
+			- A not nil instance
+			- Instance fields must not be empty
+			- setSize = 3 (provided as input)
+			- Not nil function newPolicy (LFU scenario)
+			- Nil error`, func() {
+			cache, err := NewCache[int, string](3, LFU_ALGO)
+			Expect(err).Error().ShouldNot(HaveOccurred())
+
+			Expect(cache).ShouldNot(BeNil())
+			Expect(cache.setSize).Should(Equal(3))
+			Expect(cache.newPolicy).ShouldNot(BeNil())
 		})
 
 		It(`should return:
 			- A not nil instance
 			- Instance fields must not be empty
 			- setSize = 4 (provided as input)
-			- Not nil function getItemToRemove (LRU scenario)
+			- Not nil function newPolicy (LRU scenario)
 			- Nil error`, func() {
 			cache, err := NewCache[int, string](4, LRU_ALGO)
 			Expect(err).Error().ShouldNot(HaveOccurred())
@@ -68,7 +86,7 @@ func newCacheTest() {
 			Expect(cache.sets).ShouldNot(BeNil())
 			Expect(cache.setSize).Should(Equal(4))
 			Expect(cache.setSize).ShouldNot(BeNil())
-			Expect(cache.getItemToRemove).ShouldNot(BeNil())
+			Expect(cache.newPolicy).ShouldNot(BeNil())
 		})
 	})
 
@@ -77,7 +95,7 @@ func newCacheTest() {
 			- A not nil instance
 			- Instance fields must not be empty
 			- setSize = 2 (provided as input)
-			- Not nil function getItemToRemove (LRU scenario)
+			- Not nil function newPolicy (LRU scenario)
 			- Nil error`, func() {
 			cache, err := NewCache[int, string](2)
 			Expect(err).Error().ShouldNot(HaveOccurred())
@@ -86,13 +104,13 @@ func newCacheTest() {
 			Expect(cache.sets).ShouldNot(BeNil())
 			Expect(cache.setSize).Should(Equal(2))
 			Expect(cache.setSize).ShouldNot(BeNil())
-			Expect(cache.getItemToRemove).ShouldNot(BeNil())
+			Expect(cache.newPolicy).ShouldNot(BeNil())
 		})
 	})
 
 	Context("Given a setSize = 0", func() {
 		It("should return an error cause it can't be used for mod functionality", func() {
-			Expect(NewCache[int, string](0, MRU_ALGO)).Error().Should(HaveOccurred())
+			Expect(NewCache[int, string](0, MRU_ALGO)).Error().Should(MatchError(ErrInvalidSetSize))
 		})
 	})
 
@@ -102,6 +120,43 @@ func newCacheTest() {
 		})
 	})
 
+	Context("Given NewCache with a setSize = 4", func() {
+		It("should use a square geometry: 4 sets x 4 ways", func() {
+			cache, err := NewCache[int, string](4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache.setSize).Should(Equal(4))
+			Expect(cache.ways).Should(Equal(4))
+			Expect(cache.Cap()).Should(Equal(16))
+		})
+	})
+
+	Context("Given NewCacheWithGeometry with 1024 sets and 8 ways", func() {
+		It(`should return:
+			- A not nil instance
+			- setSize = 1024 and ways = 8
+			- Cap = 8192
+			- Nil error`, func() {
+			cache, err := NewCacheWithGeometry[int, string](1024, 8, WithReplacementAlgo(MRU_ALGO))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(cache).ShouldNot(BeNil())
+			Expect(cache.setSize).Should(Equal(1024))
+			Expect(cache.ways).Should(Equal(8))
+			Expect(cache.Cap()).Should(Equal(8192))
+			Expect(cache.newPolicy).ShouldNot(BeNil())
+		})
+	})
+
+	Context("Given NewCacheWithGeometry with a non positive geometry", func() {
+		It("should return an error for numSets = 0", func() {
+			Expect(NewCacheWithGeometry[int, string](0, 8)).Error().Should(HaveOccurred())
+		})
+
+		It("should return an error for ways < 0", func() {
+			Expect(NewCacheWithGeometry[int, string](8, -1)).Error().Should(HaveOccurred())
+		})
+	})
+
 	Context("Busniess rule: Given K data type is not a primitive data type", func() {
 		Context("Given an structure", func() {
 			It("should return an error error", func() {
@@ -114,9 +169,220 @@ func newCacheTest() {
 				Expect(NewCache[*struct{}, string](4, MRU_ALGO)).Error().Should(HaveOccurred())
 			})
 		})
+
+		Context("Given an interface data type", func() {
+			It("should return an error error", func() {
+				Expect(NewCache[any, string](4, MRU_ALGO)).Error().Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("Given K data type is an integer, unsigned, float or complex data type", func() {
+		It("should not return an error", func() {
+			Expect(NewCache[int8, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[int16, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[int32, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[int64, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uint, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uint8, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uint16, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uint32, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uint64, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[uintptr, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[byte, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[rune, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[complex64, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[complex128, string](4)).Error().ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("Given K data type is a named type with a primitive underlying type", func() {
+		It("should not return an error", func() {
+			Expect(NewCache[userID, string](4)).Error().ShouldNot(HaveOccurred())
+			Expect(NewCache[tenantName, string](4)).Error().ShouldNot(HaveOccurred())
+		})
+	})
+}
+
+func newCacheWithOptionsTest() {
+	Context("Given the number of sets and ways", func() {
+		It("should return a cache with the provided geometry and options", func() {
+			cache, err := NewCacheWithOptions[int, string](WithSets(16), WithWays(4), WithReplacementAlgo(LFU_ALGO), WithDefaultTTL(time.Minute))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(cache.setSize).Should(Equal(16))
+			Expect(cache.ways).Should(Equal(4))
+			Expect(cache.Cap()).Should(Equal(64))
+			Expect(cache.defaultTTL).Should(Equal(time.Minute))
+		})
+	})
+
+	Context("Given NewCacheWithGeometry with WithSets and WithWays options", func() {
+		It("should use the geometry provided as arguments", func() {
+			opts := make([]Option, 1, 4)
+			opts[0] = WithSets(1)
+			cache, err := NewCacheWithGeometry[int, string](8, 2, opts...)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache.Cap()).Should(Equal(16))
+
+			// the options provided by the caller are not modified
+			Expect(opts[:cap(opts)][1]).Should(BeNil())
+		})
+	})
+
+	Context("Given an invalid setting", func() {
+		type invalidSetting struct {
+			name    string
+			build   func() error
+			setting string
+			value   any
+			err     error
+		}
+
+		invalidSettings := []invalidSetting{
+			{
+				name:    "no sets",
+				build:   func() error { _, err := NewCacheWithOptions[int, string](WithWays(4)); return err },
+				setting: "numSets",
+				value:   0,
+				err:     ErrInvalidSetSize,
+			},
+			{
+				name:    "no ways",
+				build:   func() error { _, err := NewCacheWithOptions[int, string](WithSets(4)); return err },
+				setting: "ways",
+				value:   0,
+				err:     ErrInvalidWays,
+			},
+			{
+				name:    "a negative setSize",
+				build:   func() error { _, err := NewCache[int, string](-1); return err },
+				setting: "setSize",
+				value:   -1,
+				err:     ErrInvalidSetSize,
+			},
+			{
+				name:    "several replacement algorithms",
+				build:   func() error { _, err := NewCache[int, string](4, MRU_ALGO, LFU_ALGO); return err },
+				setting: "replacementPolicy",
+				value:   []ReplacementPolicy{MRU_ALGO, LFU_ALGO},
+				err:     ErrConflictingOptions,
+			},
+			{
+				name:    "an unsupported key data type",
+				build:   func() error { _, err := NewCache[struct{}, string](4); return err },
+				setting: "key data type",
+				value:   reflect.TypeFor[struct{}](),
+				err:     ErrUnsupportedKeyType,
+			},
+			{
+				name: "a negative default TTL",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithDefaultTTL(-time.Second))
+					return err
+				},
+				setting: "defaultTTL",
+				value:   -time.Second,
+				err:     ErrInvalidTTL,
+			},
+			{
+				name: "a janitor without sets per pass",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithJanitor(time.Second, 0))
+					return err
+				},
+				setting: "janitorSetsPerPass",
+				value:   0,
+				err:     ErrInvalidJanitor,
+			},
+			{
+				name:    "a negative read buffer size",
+				build:   func() error { _, err := NewCacheWithGeometry[int, string](4, 4, WithReadBuffer(-1)); return err },
+				setting: "readBufferSize",
+				value:   -1,
+				err:     ErrInvalidReadBufferSize,
+			},
+			{
+				name: "an unknown replacement algorithm",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithReplacementAlgo("ARC"))
+					return err
+				},
+				setting: "replacementAlgorithm",
+				value:   ReplacementAlgo("ARC"),
+				err:     ErrUnknownReplacementAlgo,
+			},
+			{
+				name: "an eviction policy for another key data type",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithEvictionPolicy(NewLRUPolicy[string]))
+					return err
+				},
+				setting: "evictionPolicy",
+				value:   reflect.TypeFor[func() EvictionPolicy[string]](),
+				err:     ErrTypeMismatch,
+			},
+			{
+				name: "a policy constructor for another key data type",
+				build: func() error {
+					_, err := NewCache[int, string](4, PolicyConstructor[string](NewLRUPolicy[string]))
+					return err
+				},
+				setting: "evictionPolicy",
+				value:   reflect.TypeFor[func() EvictionPolicy[string]](),
+				err:     ErrTypeMismatch,
+			},
+			{
+				name: "a hasher for another key data type",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithHasher[string](HasherFunc[string](func(string) uint64 { return 0 })))
+					return err
+				},
+				setting: "hasher",
+				value:   reflect.TypeFor[HasherFunc[string]](),
+				err:     ErrTypeMismatch,
+			},
+			{
+				name: "an eviction listener for other data types",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, OnEvict(func(int, int, EvictionReason) {}))
+					return err
+				},
+				setting: "onEvict",
+				value:   reflect.TypeFor[func(int, int, EvictionReason)](),
+				err:     ErrTypeMismatch,
+			},
+			{
+				name: "a janitor without interval",
+				build: func() error {
+					_, err := NewCacheWithGeometry[int, string](4, 4, WithJanitor(0, 1))
+					return err
+				},
+				setting: "janitorInterval",
+				value:   time.Duration(0),
+				err:     ErrInvalidJanitor,
+			},
+		}
+
+		for _, invalid := range invalidSettings {
+			It(fmt.Sprintf("should return a ConfigError for %s", invalid.name), func() {
+				err := invalid.build()
+
+				var configErr *ConfigError
+				Expect(errors.As(err, &configErr)).Should(BeTrue())
+				Expect(configErr.Setting).Should(Equal(invalid.setting))
+				Expect(configErr.Value).Should(Equal(invalid.value))
+				Expect(configErr.Error()).Should(HavePrefix(invalid.setting))
+				Expect(errors.Is(err, invalid.err)).Should(BeTrue())
+			})
+		}
 	})
 }
 
+type userID int64
+
+type tenantName string
+
 func putTest() {
 	When("LRU - Testing 4-way-set-associative-cache", func() {
 		var (
@@ -128,10 +394,11 @@ func putTest() {
 			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
 			cacheTester = &Cache[int, any]{
 				setSize:               4,
-				sets:                  make(map[int]*list.List),
-				entries:               make(map[int]*list.Element),
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
 				hashKeyToIntConverter: mockedHashKeyToIntConverter,
-				getItemToRemove:       LRU_ITEM_TO_REMOVE_GETTER,
+				newPolicy:             NewLRUPolicy[int],
+				setStats:              make([]setCounters, 4),
 			}
 		})
 
@@ -144,15 +411,29 @@ func putTest() {
 				cacheTester.Put(123, "fooo")
 				cacheTester.Put(123, "fooo")
 
-				Expect(cacheTester.sets[1].Len()).Should(Equal(1))
+				Expect(cacheTester.sets[1].items.Len()).Should(Equal(1))
 
 				// Call Front or Back is the same cause it has just a single element
-				cachedItem := cacheTester.sets[1].Front().Value.(*entry[int, any])
+				cachedItem := cacheTester.sets[1].items.Front().Value.(*entry[int, any])
 				Expect(cachedItem.key).Should(Equal(123))
 				Expect(cachedItem.value).Should(Equal("fooo"))
 			})
 		})
 
+		Context("Given a key with a negative hash, as on 32-bit platforms", func() {
+			It("should save it in a valid set", func() {
+				mockedHashKeyToIntConverter.On("hashKeyToInt", 123).Return(-1)
+
+				cacheTester.Put(123, "fooo")
+				cacheTester.PutMany([]KeyValue[int, any]{{Key: 123, Value: "bar"}})
+
+				Expect(cacheTester.sets[3].items.Len()).Should(Equal(1))
+				value, found := cacheTester.Get(123)
+				Expect(found).Should(BeTrue())
+				Expect(value).Should(Equal("bar"))
+			})
+		})
+
 		Context("Given 4 invocations with the same key and different value", func() {
 			It("should have a single defined set, and the value stored in it should be the last saved value", func() {
 				mockedHashKeyToIntConverter.On("hashKeyToInt", 123).Return(1)
@@ -162,10 +443,10 @@ func putTest() {
 				cacheTester.Put(123, "thirdValue")
 				cacheTester.Put(123, "fourthValue")
 
-				Expect(cacheTester.sets[1].Len()).Should(Equal(1))
+				Expect(cacheTester.sets[1].items.Len()).Should(Equal(1))
 
 				// Call Front or Back is the same cause it has just a single element
-				cachedItem := cacheTester.sets[1].Front().Value.(*entry[int, any])
+				cachedItem := cacheTester.sets[1].items.Front().Value.(*entry[int, any])
 				Expect(cachedItem.key).Should(Equal(123))
 				Expect(cachedItem.value).Should(Equal("fourthValue"))
 			})
@@ -187,7 +468,7 @@ func putTest() {
 						cacheTester.Put(item.key, item.value)
 					}
 
-					Expect(cacheTester.sets[0].Len()).Should(Equal(4))
+					Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
 
 					// creates a list of validations
 					expectedTestValues := []struct {
@@ -223,7 +504,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -262,7 +543,7 @@ func putTest() {
 					// 100, 101, 102, 456 // 456 is a new key, 456 is removed cause algo is LRU
 					// 101, 102, 456, 100 // 100 is NOT a new key, 456 it's moved to the front of the set
 
-					Expect(cacheTester.sets[0].Len()).Should(Equal(4))
+					Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
 
 					// creates a list of validations
 					expectedTestValues := []struct {
@@ -298,7 +579,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -358,7 +639,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						cachedItem := cachedSetItems.Front().Value.(*entry[int, any])
@@ -420,7 +701,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						cachedItem := cachedSetItems.Remove(cachedSetItems.Front()).(*entry[int, any])
@@ -477,7 +758,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -490,6 +771,121 @@ func putTest() {
 		})
 	})
 
+	When("LRU - Testing 2-way-set-associative-cache with 4 sets", func() {
+		var (
+			mockedHashKeyToIntConverter *hashKeyToIntConverterMock[int]
+			cacheTester                 *Cache[int, any]
+		)
+
+		BeforeEach(func() {
+			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
+			cacheTester = &Cache[int, any]{
+				setSize:               4,
+				ways:                  2,
+				sets:                  make([]cacheSet[int, any], 4),
+				hashKeyToIntConverter: mockedHashKeyToIntConverter,
+				newPolicy:             NewLRUPolicy[int],
+				setStats:              make([]setCounters, 4),
+			}
+		})
+
+		Context("Given 3 invocations with different keys in the same set", func() {
+			It("should keep only 2 items in the set, evicting the least recently used one", func() {
+				itemsToLoad := []itemsToLoad{
+					{1, "firstValue"},
+					{2, "secondValue"},
+					{3, "thirdValue"},
+				}
+				for _, item := range itemsToLoad {
+					mockedHashKeyToIntConverter.On("hashKeyToInt", item.key).Return(5)
+					cacheTester.Put(item.key, item.value)
+				}
+
+				// 5 % 4 sets = set 1
+				Expect(cacheTester.sets[1].items.Len()).Should(Equal(2))
+				Expect(cacheTester.sets[1].items.Front().Value.(*entry[int, any]).key).Should(Equal(3))
+				Expect(cacheTester.sets[1].items.Back().Value.(*entry[int, any]).key).Should(Equal(2))
+				Expect(cacheTester.ListAll()).ShouldNot(HaveKey(1))
+			})
+		})
+	})
+
+	When("LFU - Testing 4-way-set-associative-cache", func() {
+		var (
+			mockedHashKeyToIntConverter *hashKeyToIntConverterMock[int]
+			cacheTester                 *Cache[int, any]
+		)
+
+		BeforeEach(func() {
+			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
+			cacheTester = &Cache[int, any]{
+				setSize:               4,
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
+				hashKeyToIntConverter: mockedHashKeyToIntConverter,
+				newPolicy:             NewLFUPolicy[int],
+				setStats:              make([]setCounters, 4),
+			}
+		})
+
+		Context("Given a hot set of keys and a one-off scan in the same set", func() {
+			It("should keep the hot keys and evict the scanned keys", func() {
+				for _, key := range []int{1, 2, 3, 10, 11, 12} {
+					mockedHashKeyToIntConverter.On("hashKeyToInt", key).Return(0)
+				}
+
+				// 1, 2 and 3 are the hot set, they are read several times
+				cacheTester.Put(1, "one")
+				cacheTester.Put(2, "two")
+				cacheTester.Put(3, "three")
+				for i := 0; i < 3; i++ {
+					cacheTester.Get(1)
+					cacheTester.Get(2)
+					cacheTester.Get(3)
+				}
+
+				// one-off scan
+				cacheTester.Put(10, "ten")
+				cacheTester.Put(11, "eleven")
+				cacheTester.Put(12, "twelve")
+
+				// Test scenario explanation
+				// frequencies: 1=4, 2=4, 3=4, 10=1 (set is full)
+				// 11 is a new key, 10 is removed cause it's the least frequently used
+				// 12 is a new key, 11 is removed cause it's the least frequently used
+				Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
+				Expect(cacheTester.ListAll()).Should(HaveKey(1))
+				Expect(cacheTester.ListAll()).Should(HaveKey(2))
+				Expect(cacheTester.ListAll()).Should(HaveKey(3))
+				Expect(cacheTester.ListAll()).Should(HaveKey(12))
+				Expect(cacheTester.ListAll()).ShouldNot(HaveKey(10))
+				Expect(cacheTester.ListAll()).ShouldNot(HaveKey(11))
+			})
+		})
+
+		Context("Given all entries with the same frequency", func() {
+			It("should break the tie by recency, removing the least recently used entry", func() {
+				for _, key := range []int{1, 2, 3, 4, 5} {
+					mockedHashKeyToIntConverter.On("hashKeyToInt", key).Return(0)
+				}
+
+				cacheTester.Put(1, "one")
+				cacheTester.Put(2, "two")
+				cacheTester.Put(3, "three")
+				cacheTester.Put(4, "four")
+				cacheTester.Get(1)
+				cacheTester.Get(2)
+				cacheTester.Get(3)
+				cacheTester.Get(4)
+				cacheTester.Put(5, "five")
+
+				Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
+				Expect(cacheTester.ListAll()).ShouldNot(HaveKey(1))
+				Expect(cacheTester.sets[0].items.Front().Value.(*entry[int, any]).key).Should(Equal(5))
+			})
+		})
+	})
+
 	When("MRU - Testing 4-way-set-associative-cache", func() {
 		var (
 			mockedHashKeyToIntConverter *hashKeyToIntConverterMock[int]
@@ -500,10 +896,11 @@ func putTest() {
 			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
 			cacheTester = &Cache[int, any]{
 				setSize:               4,
-				sets:                  make(map[int]*list.List),
-				entries:               make(map[int]*list.Element),
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
 				hashKeyToIntConverter: mockedHashKeyToIntConverter,
-				getItemToRemove:       MRU_ITEM_TO_REMOVE_GETTER,
+				newPolicy:             NewMRUPolicy[int],
+				setStats:              make([]setCounters, 4),
 			}
 		})
 
@@ -516,10 +913,10 @@ func putTest() {
 				cacheTester.Put(123, "fooo")
 				cacheTester.Put(123, "fooo")
 
-				Expect(cacheTester.sets[1].Len()).Should(Equal(1))
+				Expect(cacheTester.sets[1].items.Len()).Should(Equal(1))
 
 				// Call Front or Back is the same cause it has just a single element
-				cachedItem := cacheTester.sets[1].Front().Value.(*entry[int, any])
+				cachedItem := cacheTester.sets[1].items.Front().Value.(*entry[int, any])
 				Expect(cachedItem.key).Should(Equal(123))
 				Expect(cachedItem.value).Should(Equal("fooo"))
 			})
@@ -534,10 +931,10 @@ func putTest() {
 				cacheTester.Put(123, "thirdValue")
 				cacheTester.Put(123, "fourthValue")
 
-				Expect(cacheTester.sets[1].Len()).Should(Equal(1))
+				Expect(cacheTester.sets[1].items.Len()).Should(Equal(1))
 
 				// Call Front or Back is the same cause it has just a single element
-				cachedItem := cacheTester.sets[1].Front().Value.(*entry[int, any])
+				cachedItem := cacheTester.sets[1].items.Front().Value.(*entry[int, any])
 				Expect(cachedItem.key).Should(Equal(123))
 				Expect(cachedItem.value).Should(Equal("fourthValue"))
 			})
@@ -566,7 +963,7 @@ func putTest() {
 					// 123, 456, 789, 101 // 101 is a new key, 100 is removed cause algo is MRU
 					// 123, 456, 789, 102 // 102 is a new key, 101 is removed cause algo is MRU
 
-					Expect(cacheTester.sets[0].Len()).Should(Equal(4))
+					Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
 
 					// creates a list of validations
 					expectedTestValues := []struct {
@@ -602,7 +999,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -641,7 +1038,7 @@ func putTest() {
 					// 123, 789, 102, 456 // 456 is NOT a new key, it's moved in front of the set.
 					// 123, 789, 102, 100 // 100 is a new key, 456 is removed cause algo is MRU
 
-					Expect(cacheTester.sets[0].Len()).Should(Equal(4))
+					Expect(cacheTester.sets[0].items.Len()).Should(Equal(4))
 
 					// creates a list of validations
 					expectedTestValues := []struct {
@@ -677,7 +1074,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -737,7 +1134,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						cachedItem := cachedSetItems.Front().Value.(*entry[int, any])
@@ -794,7 +1191,7 @@ func putTest() {
 					}
 
 					for _, expectedItem := range expectedTestValues {
-						cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+						cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 						Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 						// The item to be validated is being removed from the set in order to validate the next item configured as expected
@@ -856,7 +1253,7 @@ func putTest() {
 						}
 
 						for _, expectedItem := range expectedTestValues {
-							cachedSetItems := cacheTester.sets[expectedItem.setIndex]
+							cachedSetItems := cacheTester.sets[expectedItem.setIndex].items
 							Expect(cachedSetItems.Len()).Should(Equal(expectedItem.expectedSetLength))
 
 							cachedItem := cachedSetItems.Remove(cachedSetItems.Front()).(*entry[int, any])
@@ -882,10 +1279,11 @@ func getTest() {
 			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
 			preloadedCache = &Cache[int, any]{
 				setSize:               4,
-				sets:                  make(map[int]*list.List),
-				entries:               make(map[int]*list.Element),
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
 				hashKeyToIntConverter: mockedHashKeyToIntConverter,
-				getItemToRemove:       LRU_ITEM_TO_REMOVE_GETTER,
+				newPolicy:             NewLRUPolicy[int],
+				setStats:              make([]setCounters, 4),
 			}
 
 			preloadedItems := []struct {
@@ -1003,10 +1401,11 @@ func listAllTest() {
 			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
 			preloadedCache = &Cache[int, any]{
 				setSize:               4,
-				sets:                  make(map[int]*list.List),
-				entries:               make(map[int]*list.Element),
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
 				hashKeyToIntConverter: mockedHashKeyToIntConverter,
-				getItemToRemove:       LRU_ITEM_TO_REMOVE_GETTER,
+				newPolicy:             NewLRUPolicy[int],
+				setStats:              make([]setCounters, 4),
 			}
 
 			for _, item := range preloadedItems {
@@ -1074,16 +1473,19 @@ func deleteTest() {
 			mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
 			preloadedCache = &Cache[int, any]{
 				setSize:               4,
-				sets:                  make(map[int]*list.List),
-				entries:               make(map[int]*list.Element),
+				ways:                  4,
+				sets:                  make([]cacheSet[int, any], 4),
 				hashKeyToIntConverter: mockedHashKeyToIntConverter,
-				getItemToRemove:       LRU_ITEM_TO_REMOVE_GETTER,
+				newPolicy:             NewLRUPolicy[int],
+				setStats:              make([]setCounters, 4),
 			}
 
 			for _, item := range preloadedItems {
 				mockedHashKeyToIntConverter.On("hashKeyToInt", item.key).Return(item.mockedHashedKey)
 				preloadedCache.Put(item.key, item.value)
 			}
+			// a key that is never saved, Delete still needs its set to look it up
+			mockedHashKeyToIntConverter.On("hashKeyToInt", 999).Return(3)
 		})
 
 		Context("Trying to delete an item that doesn't exist", func() {
@@ -1181,6 +1583,24 @@ func hashKeyToIntTest() {
 			})
 		})
 
+		Context("Given '1' (int64), '1' (uint64) and '1' (userID) as input", func() {
+			It("should return different results", func() {
+				int64Result := functionInvoker.hashKeyToInt(int64(1))
+				uint64Result := functionInvoker.hashKeyToInt(uint64(1))
+				userIDResult := functionInvoker.hashKeyToInt(userID(1))
+				Expect(int64Result).ShouldNot(Equal(uint64Result))
+				Expect(int64Result).ShouldNot(Equal(userIDResult))
+				Expect(uint64Result).ShouldNot(Equal(userIDResult))
+			})
+		})
+
+		Context("Given '1' (complex64) and '1' (complex128) as input", func() {
+			It("should return different results", func() {
+				comparissionResult := functionInvoker.hashKeyToInt(complex64(1)) == functionInvoker.hashKeyToInt(complex128(1))
+				Expect(comparissionResult).Should(BeFalse())
+			})
+		})
+
 		Context("Given 'true' (string) as input and 'true' (bool)", func() {
 			It("should return FALSE if compare their results", func() {
 				comparissionResult := functionInvoker.hashKeyToInt("true") == functionInvoker.hashKeyToInt(true)
@@ -1273,4 +1693,42 @@ func concurrencyTest() {
 			wg.Wait() // Wait for all goroutines to finish
 		})
 	})
+
+	When("Mixing operations on every set with operations on the whole cache", func() {
+		It("Should keep every set consistent and not return race condition errors if run test with -race flag", func() {
+			cache, err := NewCacheWithGeometry[int, int](16, 4, WithMissClassification())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var wg sync.WaitGroup
+			numGoroutines := 16
+
+			wg.Add(numGoroutines + 1)
+			for i := 0; i < numGoroutines; i++ {
+				go func(i int) {
+					defer wg.Done()
+					for key := i * 100; key < (i+1)*100; key++ {
+						cache.Put(key, key)
+						_, _ = cache.Get(key - 1)
+						if key%10 == 0 {
+							cache.Delete(key)
+						}
+					}
+				}(i)
+			}
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 10; i++ {
+					cache.ListAll()
+					cache.Stats()
+				}
+			}()
+			wg.Wait()
+
+			for i := range cache.sets {
+				Expect(cache.sets[i].len()).Should(Equal(len(cache.sets[i].entries)))
+				Expect(cache.sets[i].len()).Should(BeNumerically("<=", 4))
+			}
+			Expect(cache.ListAll()).Should(HaveLen(cache.Len()))
+		})
+	})
 }
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+)
+
+func BenchmarkHashKeyToInt(b *testing.B) {
+	b.Run("int", func(b *testing.B) {
+		functionInvoker := new(hashKeyToIntImpl[int])
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			functionInvoker.hashKeyToInt(i)
+		}
+	})
+
+	b.Run("string", func(b *testing.B) {
+		functionInvoker := new(hashKeyToIntImpl[string])
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			functionInvoker.hashKeyToInt("tenant-42:user-1")
+		}
+	})
+
+	b.Run("fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fmtHashKeyToInt(i)
+		}
+	})
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	cache, err := NewCacheWithGeometry[int, int](1024, 8)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 1024; i++ {
+		cache.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(i % 1024)
+	}
+}
+
+func BenchmarkGetHitString(b *testing.B) {
+	cache, err := NewCacheWithGeometry[string, int](1024, 8)
+	if err != nil {
+		b.Fatal(err)
+	}
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+		cache.Put(keys[i], i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	cache, err := NewCacheWithGeometry[int, int](1024, 8)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Put(i, i)
+	}
+}
+
+// BenchmarkGetHitParallel measures how Get scales with the number of goroutines,
+// run it with -cpu, e.g. -cpu 1,2,4,8, to compare.
+func BenchmarkGetHitParallel(b *testing.B) {
+	b.Run("exclusive", func(b *testing.B) {
+		benchmarkGetHitParallel(b)
+	})
+
+	b.Run("read buffer", func(b *testing.B) {
+		benchmarkGetHitParallel(b, WithReadBuffer(64))
+	})
+
+	b.Run("FIFO", func(b *testing.B) {
+		benchmarkGetHitParallel(b, WithReplacementAlgo(FIFO_ALGO))
+	})
+}
+
+func benchmarkGetHitParallel(b *testing.B, opts ...Option) {
+	// few sets, so goroutines keep reading the same sets
+	cache, err := NewCacheWithGeometry[int, int](4, 64, opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 256; i++ {
+		cache.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get(i % 256)
+			i += 7
+		}
+	})
+}
+
+// BenchmarkMixedParallel measures a read-mostly workload, 90% Get and 10% Put, with the number of goroutines.
+func BenchmarkMixedParallel(b *testing.B) {
+	cache, err := NewCacheWithGeometry[int, int](1024, 8)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 1024; i++ {
+		cache.Put(i, i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%10 == 0 {
+				cache.Put(i%2048, i)
+			} else {
+				cache.Get(i % 1024)
+			}
+			i += 7
+		}
+	})
+}
+
+// BenchmarkGetMany compares looking up 128 keys with GetMany against invoking Get for every key.
+func BenchmarkGetMany(b *testing.B) {
+	cache, err := NewCacheWithGeometry[int, int](64, 8)
+	if err != nil {
+		b.Fatal(err)
+	}
+	keys := make([]int, 128)
+	for i := range keys {
+		keys[i] = i * 3
+		cache.Put(keys[i], i)
+	}
+
+	b.Run("GetMany", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cache.GetMany(keys)
+		}
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, key := range keys {
+				cache.Get(key)
+			}
+		}
+	})
+}
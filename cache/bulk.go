@@ -0,0 +1,120 @@
+package cache
+
+import "slices"
+
+// KeyValue is a key-value pair saved with PutMany.
+type KeyValue[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// setOperation is a single key of a bulk operation: the set of the key in the upper 32 bits
+// and the position of the key in the input in the lower 32 bits, so sorting operations sorts them
+// by set and then by position. Sorting integers is several times faster than sorting structs with a
+// comparison function, and neither the number of sets nor the number of keys of a call can reach 2^32.
+type setOperation uint64
+
+func newSetOperation(setIndex, position int) setOperation {
+	return setOperation(uint64(setIndex)<<32 | uint64(uint32(position)))
+}
+
+func (o setOperation) setIndex() int {
+	return int(o >> 32)
+}
+
+func (o setOperation) position() int {
+	return int(uint32(o))
+}
+
+// groupBySet hashes every key once and returns the positions of the keys sorted by set,
+// keeping the input order of the keys of the same set.
+func (c *Cache[K, V]) groupBySet(count int, keyAt func(position int) K) []setOperation {
+	operations := make([]setOperation, count)
+	for position := range operations {
+		operations[position] = newSetOperation(c.setIndexOf(keyAt(position)), position)
+	}
+	slices.Sort(operations)
+	return operations
+}
+
+// forEachSet invokes apply for every group of operations of the same set, holding the mutex of that set,
+// so every affected set is locked once.
+func (c *Cache[K, V]) forEachSet(operations []setOperation, apply func(setIndex int, operations []setOperation)) {
+	for start := 0; start < len(operations); {
+		end := start + 1
+		for end < len(operations) && operations[end].setIndex() == operations[start].setIndex() {
+			end++
+		}
+
+		c.applyToSet(operations[start].setIndex(), operations[start:end], apply)
+		start = end
+	}
+}
+
+// applyToSet invokes apply with the operations of the provided set while holding its mutex.
+func (c *Cache[K, V]) applyToSet(setIndex int, operations []setOperation, apply func(setIndex int, operations []setOperation)) {
+	set := &c.sets[setIndex]
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	apply(setIndex, operations)
+}
+
+// GetMany looks up every provided key as Get does, but locking every affected set once.
+// It returns the values of the keys found and the keys missing, in the order they were provided.
+// Duplicated keys are looked up once per occurrence.
+func (c *Cache[K, V]) GetMany(keys []K) (found map[K]V, missing []K) {
+	found = make(map[K]V, len(keys))
+	missingPositions := make([]bool, len(keys))
+	operations := c.groupBySet(len(keys), func(position int) K { return keys[position] })
+
+	c.forEachSet(operations, func(setIndex int, operations []setOperation) {
+		for _, operation := range operations {
+			key := keys[operation.position()]
+			if value, ok := c.get(setIndex, key); ok {
+				found[key] = value
+			} else {
+				missingPositions[operation.position()] = true
+			}
+		}
+	})
+
+	for position, isMissing := range missingPositions {
+		if isMissing {
+			missing = append(missing, keys[position])
+		}
+	}
+	return found, missing
+}
+
+// PutMany saves every provided key-value pair as Put does, but locking every affected set once.
+// The sets are updated in ascending set order and the pairs of the same set in the order they were provided,
+// so evictions are deterministic: a later pair of a full set may evict an earlier one,
+// and the last value of a duplicated key wins.
+func (c *Cache[K, V]) PutMany(items []KeyValue[K, V]) {
+	expiresAt := c.expiresAt(c.defaultTTL)
+	operations := c.groupBySet(len(items), func(position int) K { return items[position].Key })
+
+	c.forEachSet(operations, func(setIndex int, operations []setOperation) {
+		for _, operation := range operations {
+			item := items[operation.position()]
+			c.put(setIndex, item.Key, item.Value, expiresAt, nil)
+		}
+	})
+}
+
+// DeleteMany removes every provided key as Delete does, but locking every affected set once.
+// It returns the number of entries removed.
+func (c *Cache[K, V]) DeleteMany(keys []K) int {
+	removed := 0
+	operations := c.groupBySet(len(keys), func(position int) K { return keys[position] })
+
+	c.forEachSet(operations, func(setIndex int, operations []setOperation) {
+		for _, operation := range operations {
+			if c.remove(setIndex, keys[operation.position()], EvictionReasonDeleted) {
+				removed++
+			}
+		}
+	})
+	return removed
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing bulk operations", func() {
+	Describe("testing function GetMany", getManyTest)
+	Describe("testing function PutMany", putManyTest)
+	Describe("testing function DeleteMany", deleteManyTest)
+})
+
+func getManyTest() {
+	Context("Given keys found, missing and expired", func() {
+		It("should return the values found and the keys missing in the provided order", func() {
+			cacheTester, clock, hashes := newModuloCacheTester(4, 2)
+			cacheTester.Put(1, "one")
+			cacheTester.Put(4, "four")
+			cacheTester.PutWithTTL(2, "two", time.Second)
+			clock.advance(time.Second)
+			*hashes = 0
+
+			found, missing := cacheTester.GetMany([]int{7, 1, 2, 4, 3})
+			Expect(found).Should(Equal(map[int]string{1: "one", 4: "four"}))
+			Expect(missing).Should(Equal([]int{7, 2, 3}))
+			Expect(*hashes).Should(Equal(5))
+
+			stats := cacheTester.Stats()
+			Expect(stats.Hits).Should(BeEquivalentTo(2))
+			Expect(stats.Misses).Should(BeEquivalentTo(3))
+			Expect(stats.Evictions[EvictionReasonExpired]).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given hits", func() {
+		It("should promote the keys as Get does", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 2)
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(4, "four")
+
+			cacheTester.GetMany([]int{0})
+			cacheTester.Put(8, "eight")
+			Expect(cacheTester.ListAll()).Should(Equal(map[int]string{0: "zero", 8: "eight"}))
+		})
+	})
+
+	Context("Given no keys", func() {
+		It("should return nothing", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 2)
+			found, missing := cacheTester.GetMany(nil)
+			Expect(found).Should(BeEmpty())
+			Expect(missing).Should(BeEmpty())
+		})
+	})
+}
+
+func putManyTest() {
+	Context("Given more pairs than ways for the same set", func() {
+		It("should apply them in the provided order, evicting the earlier ones", func() {
+			cacheTester, _, hashes := newModuloCacheTester(4, 2)
+			var evicted []int
+			cacheTester.onEvict = func(key int, value string, reason EvictionReason) {
+				evicted = append(evicted, key)
+			}
+
+			cacheTester.PutMany([]KeyValue[int, string]{
+				{Key: 0, Value: "zero"},
+				{Key: 1, Value: "one"},
+				{Key: 4, Value: "four"},
+				{Key: 8, Value: "eight"},
+				{Key: 1, Value: "uno"},
+			})
+
+			Expect(cacheTester.ListAll()).Should(Equal(map[int]string{4: "four", 8: "eight", 1: "uno"}))
+			Expect(evicted).Should(Equal([]int{0, 1}))
+			Expect(*hashes).Should(Equal(5))
+
+			stats := cacheTester.Stats()
+			Expect(stats.Insertions).Should(BeEquivalentTo(4))
+			Expect(stats.Updates).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given a default TTL", func() {
+		It("should apply it to every pair", func() {
+			cacheTester, clock, _ := newModuloCacheTester(4, 2, WithDefaultTTL(time.Second))
+			cacheTester.PutMany([]KeyValue[int, string]{{Key: 0, Value: "zero"}, {Key: 1, Value: "one"}})
+			clock.advance(time.Second)
+
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+		})
+	})
+}
+
+func deleteManyTest() {
+	Context("Given keys found and missing", func() {
+		It("should remove the keys found and return how many were removed", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 2)
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(5, "five")
+
+			Expect(cacheTester.DeleteMany([]int{5, 3, 0, 0})).Should(Equal(2))
+			Expect(cacheTester.ListAll()).Should(Equal(map[int]string{1: "one"}))
+			Expect(cacheTester.Stats().Deletes).Should(BeEquivalentTo(2))
+		})
+	})
+}
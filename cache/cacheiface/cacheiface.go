@@ -6,3 +6,12 @@ type Cache[K comparable, V any] interface {
 	ListAll() map[K]V
 	Delete(key K)
 }
+
+// PeekableCache is a Cache that can also be inspected without changing its replacement order.
+type PeekableCache[K comparable, V any] interface {
+	Cache[K, V]
+	// Peek returns the value associated to the key, as Get does, without promoting the key.
+	Peek(key K) (V, bool)
+	// Contains returns true if the key is present, without promoting the key.
+	Contains(key K) bool
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for invalid setting values. They are usually caused by a wrong configuration,
+// e.g. a geometry or a replacement algorithm loaded from a configuration file.
+var (
+	// ErrInvalidSetSize is returned for a non positive setSize or number of sets.
+	ErrInvalidSetSize = errors.New("invalid set size")
+	// ErrInvalidWays is returned for a non positive number of ways.
+	ErrInvalidWays = errors.New("invalid number of ways")
+	// ErrUnknownReplacementAlgo is returned when a replacement algorithm is not one of the supported ones.
+	ErrUnknownReplacementAlgo = errors.New("unknown replacement algorithm")
+	// ErrInvalidTTL is returned for a negative default TTL.
+	ErrInvalidTTL = errors.New("invalid TTL")
+	// ErrInvalidReadBufferSize is returned for a negative read buffer size.
+	ErrInvalidReadBufferSize = errors.New("invalid read buffer size")
+	// ErrInvalidJanitor is returned for a non positive janitor interval or number of sets per pass.
+	ErrInvalidJanitor = errors.New("invalid janitor settings")
+)
+
+// Sentinel errors for invalid combinations of types and options. They are usually caused by a programming error,
+// e.g. an option built for other key data types, and can't be fixed by changing the configuration.
+var (
+	// ErrUnsupportedKeyType is returned when the key data type can't be hashed without a custom hasher.
+	ErrUnsupportedKeyType = errors.New("unsupported key data type")
+	// ErrTypeMismatch is returned when a hasher, an eviction policy or an eviction listener
+	// was built for other key or value data types than the ones of the cache.
+	ErrTypeMismatch = errors.New("option data types mismatch")
+	// ErrConflictingOptions is returned when options that exclude each other are combined,
+	// e.g. more than one replacement algorithm, or a hash seed and a custom hasher.
+	ErrConflictingOptions = errors.New("conflicting options")
+)
+
+// ConfigError is returned when a cache is created with an invalid setting.
+// It can be extracted with errors.As to know which setting and which value were rejected,
+// and it wraps one of the sentinel errors above, so errors.Is can be used to check the kind of the rejection.
+type ConfigError struct {
+	// Setting is the name of the rejected setting, e.g. "ways".
+	Setting string
+	// Value is the rejected value, or nil if the setting is rejected regardless of its value.
+	Value any
+	// Reason describes why the value was rejected.
+	Reason string
+	// Err is the sentinel error matching the rejection, e.g. ErrInvalidSetSize.
+	// It's returned by Unwrap, so errors.Is can be used to check it.
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s %s", e.Setting, e.Reason)
+	}
+	return fmt.Sprintf("%s provided '%v', %s", e.Setting, e.Value, e.Reason)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,64 @@
+package cache
+
+// EvictionReason describes why an entry left the cache.
+type EvictionReason int
+
+const (
+	// EvictionReasonCapacity means the entry was chosen by the eviction policy to make room in a full set.
+	EvictionReasonCapacity EvictionReason = iota
+	// EvictionReasonDeleted means the entry was removed with Delete.
+	EvictionReasonDeleted
+	// EvictionReasonReplaced means the value was overwritten by a new Put for the same key.
+	// The notified value is the previous one, which may be identical to the new one.
+	EvictionReasonReplaced
+	// EvictionReasonExpired means the entry outlived its time to live.
+	EvictionReasonExpired
+	// EvictionReasonCleared means the entry was removed by Clear.
+	EvictionReasonCleared
+)
+
+// String returns a human readable representation of the reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonCapacity:
+		return "capacity"
+	case EvictionReasonDeleted:
+		return "deleted"
+	case EvictionReasonReplaced:
+		return "replaced"
+	case EvictionReasonExpired:
+		return "expired"
+	case EvictionReasonCleared:
+		return "cleared"
+	}
+	return "unknown"
+}
+
+// eviction is a removed key-value pair waiting to be notified to the eviction listener.
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// recordEviction updates the stats and queues the removed key-value pair until the mutex of the set is released.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) recordEviction(set *cacheSet[K, V], key K, value V, reason EvictionReason) {
+	c.stats.recordRemoval(reason)
+	if c.onEvict == nil {
+		return
+	}
+	set.evictions = append(set.evictions, eviction[K, V]{key: key, value: value, reason: reason})
+}
+
+// unlock releases the mutex of the set and then notifies the evictions recorded while it was held,
+// so the eviction listener can safely invoke the cache again.
+func (c *Cache[K, V]) unlock(set *cacheSet[K, V]) {
+	evictions := set.evictions
+	set.evictions = nil
+	set.mutex.Unlock()
+
+	for _, evicted := range evictions {
+		c.onEvict(evicted.key, evicted.value, evicted.reason)
+	}
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing eviction listener", func() {
+	Describe("testing option OnEvict", onEvictTest)
+	Describe("testing function Clear", clearTest)
+	Describe("testing function DeleteFunc", deleteFuncTest)
+	Describe("testing EvictionReason", evictionReasonTest)
+})
+
+type evictedItem struct {
+	key    string
+	value  int
+	reason EvictionReason
+}
+
+func onEvictTest() {
+	var (
+		clock       *fakeClock
+		evicted     []evictedItem
+		cacheTester *Cache[string, int]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		evicted = nil
+		// a single set with 2 ways, so every key competes for the same slots
+		cacheTester, err = NewCacheWithGeometry[string, int](1, 2, WithClock(clock.now), OnEvict(func(key string, value int, reason EvictionReason) {
+			evicted = append(evicted, evictedItem{key, value, reason})
+		}))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a full set and a new key", func() {
+		It("should notify the evicted entry with capacity reason", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+			cacheTester.Put("c", 3)
+
+			Expect(evicted).Should(Equal([]evictedItem{{"a", 1, EvictionReasonCapacity}}))
+		})
+	})
+
+	Context("Given an existing key that is deleted", func() {
+		It("should notify the entry with deleted reason", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Delete("a")
+			cacheTester.Delete("missing")
+
+			Expect(evicted).Should(Equal([]evictedItem{{"a", 1, EvictionReasonDeleted}}))
+		})
+	})
+
+	Context("Given an existing key that is overwritten", func() {
+		It("should notify the previous value with replaced reason", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("a", 2)
+
+			Expect(evicted).Should(Equal([]evictedItem{{"a", 1, EvictionReasonReplaced}}))
+		})
+	})
+
+	Context("Given an existing key that is overwritten with the same pointer", func() {
+		It("should notify the pointer that is still cached with replaced reason", func() {
+			var evictedValues []*int
+			cache, err := NewCacheWithGeometry[string, *int](1, 2, OnEvict(func(key string, value *int, reason EvictionReason) {
+				evictedValues = append(evictedValues, value)
+			}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			value := new(int)
+			cache.Put("a", value)
+			cache.Put("a", value)
+
+			Expect(evictedValues).Should(HaveLen(1))
+			Expect(evictedValues[0]).Should(BeIdenticalTo(value))
+			cached, found := cache.Get("a")
+			Expect(found).Should(BeTrue())
+			Expect(cached).Should(BeIdenticalTo(value))
+		})
+	})
+
+	Context("Given an expired key that is overwritten before being removed", func() {
+		It("should notify the previous value with expired reason", func() {
+			cacheTester.PutWithTTL("a", 1, time.Second)
+			clock.advance(2 * time.Second)
+			cacheTester.Put("a", 2)
+
+			Expect(evicted).Should(Equal([]evictedItem{{"a", 1, EvictionReasonExpired}}))
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"a": 2}))
+		})
+	})
+
+	Context("Given an expired key that is read", func() {
+		It("should notify the entry with expired reason", func() {
+			cacheTester.PutWithTTL("a", 1, time.Second)
+			clock.advance(time.Second)
+			cacheTester.Get("a")
+
+			Expect(evicted).Should(Equal([]evictedItem{{"a", 1, EvictionReasonExpired}}))
+		})
+	})
+
+	Context("Given a listener that invokes the cache", func() {
+		It("should not deadlock", func() {
+			reentrantCache, err := NewCacheWithGeometry[string, int](1, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			reentrantCache.onEvict = func(key string, value int, reason EvictionReason) {
+				reentrantCache.Get(key)
+				reentrantCache.ListAll()
+			}
+
+			reentrantCache.Put("a", 1)
+			reentrantCache.Put("b", 2)
+			Expect(reentrantCache.ListAll()).Should(Equal(map[string]int{"b": 2}))
+		})
+	})
+
+	Context("Given a listener with different key or value data types", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[string, string](1, 2, OnEvict(func(key string, value int, reason EvictionReason) {}))).Error().Should(HaveOccurred())
+		})
+	})
+}
+
+func clearTest() {
+	Context("Given a cache with 3 entries", func() {
+		It("should remove every entry and notify them with cleared reason", func() {
+			evicted := map[string]EvictionReason{}
+			cacheTester, err := NewCacheWithGeometry[string, int](2, 2, OnEvict(func(key string, value int, reason EvictionReason) {
+				evicted[key] = reason
+			}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+			cacheTester.Put("c", 3)
+			cacheTester.Clear()
+
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+			for i := range cacheTester.sets {
+				Expect(cacheTester.sets[i].items).Should(BeNil())
+			}
+			Expect(evicted).Should(Equal(map[string]EvictionReason{
+				"a": EvictionReasonCleared,
+				"b": EvictionReasonCleared,
+				"c": EvictionReasonCleared,
+			}))
+
+			cacheTester.Put("a", 4)
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"a": 4}))
+		})
+
+		It("should empty every set before notifying the listener", func() {
+			var cacheTester *Cache[string, int]
+			lengths := []int{}
+			cacheTester, err := NewCacheWithGeometry[string, int](4, 2, OnEvict(func(key string, value int, reason EvictionReason) {
+				// the listener runs without any lock, so it can read the cache
+				lengths = append(lengths, cacheTester.Len())
+			}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+			cacheTester.Put("c", 3)
+			cacheTester.Clear()
+
+			Expect(lengths).Should(Equal([]int{0, 0, 0}))
+		})
+	})
+}
+
+func deleteFuncTest() {
+	var (
+		clock       *fakeClock
+		evicted     map[string]EvictionReason
+		cacheTester *Cache[string, int]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		evicted = map[string]EvictionReason{}
+		cacheTester, err = NewCacheWithGeometry[string, int](4, 4, WithClock(clock.now), OnEvict(func(key string, value int, reason EvictionReason) {
+			evicted[key] = reason
+		}))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cacheTester.Put("tenant-42:home", 1)
+		cacheTester.Put("tenant-42:profile", 2)
+		cacheTester.Put("tenant-7:home", 3)
+		cacheTester.Put("tenant-7:profile", 42)
+	})
+
+	Context("Given a predicate matching some entries", func() {
+		It("should remove them, notify them with deleted reason and return how many were removed", func() {
+			removed := cacheTester.DeleteFunc(func(key string, value int) bool {
+				return strings.HasPrefix(key, "tenant-42:") || value == 42
+			})
+
+			Expect(removed).Should(Equal(3))
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"tenant-7:home": 3}))
+			Expect(cacheTester.Len()).Should(Equal(1))
+			Expect(evicted).Should(Equal(map[string]EvictionReason{
+				"tenant-42:home":    EvictionReasonDeleted,
+				"tenant-42:profile": EvictionReasonDeleted,
+				"tenant-7:profile":  EvictionReasonDeleted,
+			}))
+			Expect(cacheTester.Stats().Deletes).Should(BeEquivalentTo(3))
+		})
+	})
+
+	Context("Given a predicate matching nothing", func() {
+		It("should not remove anything", func() {
+			Expect(cacheTester.DeleteFunc(func(string, int) bool { return false })).Should(BeZero())
+			Expect(cacheTester.Len()).Should(Equal(4))
+		})
+	})
+
+	Context("Given expired entries", func() {
+		It("should not pass them to the predicate", func() {
+			cacheTester.PutWithTTL("tenant-42:expiring", 5, time.Second)
+			clock.advance(time.Second)
+
+			var keys []string
+			cacheTester.DeleteFunc(func(key string, value int) bool {
+				keys = append(keys, key)
+				return false
+			})
+			Expect(keys).Should(ConsistOf("tenant-42:home", "tenant-42:profile", "tenant-7:home", "tenant-7:profile"))
+		})
+	})
+}
+
+func evictionReasonTest() {
+	Context("Given every eviction reason", func() {
+		It("should return a readable name", func() {
+			Expect(EvictionReasonCapacity.String()).Should(Equal("capacity"))
+			Expect(EvictionReasonDeleted.String()).Should(Equal("deleted"))
+			Expect(EvictionReasonReplaced.String()).Should(Equal("replaced"))
+			Expect(EvictionReasonExpired.String()).Should(Equal("expired"))
+			Expect(EvictionReasonCleared.String()).Should(Equal("cleared"))
+			Expect(EvictionReason(100).String()).Should(Equal("unknown"))
+		})
+	})
+}
@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// Hasher computes the hash of a key, the cache uses it to choose the set where the key is saved.
+// Keys that are equal must get the same hash. See WithHasher.
+type Hasher[K comparable] interface {
+	Hash(key K) uint64
+}
+
+// HasherFunc is an adapter to use an ordinary function as Hasher.
+type HasherFunc[K comparable] func(key K) uint64
+
+// Hash returns f(key).
+func (f HasherFunc[K]) Hash(key K) uint64 {
+	return f(key)
+}
+
+type hashKeyToIntConverter[K comparable] interface {
+	hashKeyToInt(key K) int
+}
+
+// hasherConverter adapts a Hasher to the internal hashKeyToIntConverter.
+type hasherConverter[K comparable] struct {
+	hasher Hasher[K]
+}
+
+// hashKeyToInt returns the hash computed by the Hasher, truncated to a non negative int.
+func (h *hasherConverter[K]) hashKeyToInt(key K) int {
+	return int(h.hasher.Hash(key) & math.MaxInt)
+}
+
+// hashKeyToIntImpl is the built-in hashKeyToIntConverter. Its zero value uses FNV-1a,
+// when it's seeded it uses SipHash-2-4 keyed with (k0, k1) instead.
+// keyType describes K, so named types whose underlying type is primitive are hashed without reflection.
+// Without it, as in the zero value, they are hashed through reflection, which allocates.
+type hashKeyToIntImpl[K comparable] struct {
+	seeded  bool
+	k0, k1  uint64
+	keyType keyType
+}
+
+// keyType holds the kind and the name of a key data type, as reported by reflect and fmt's %T.
+type keyType struct {
+	kind reflect.Kind
+	name string
+}
+
+// newHashKeyToIntImpl returns the built-in hashKeyToIntConverter, seeded according to the provided config.
+func newHashKeyToIntImpl[K comparable](cfg *config) (*hashKeyToIntImpl[K], error) {
+	keyDataType := reflect.TypeFor[K]()
+	impl := &hashKeyToIntImpl[K]{keyType: keyType{kind: keyDataType.Kind(), name: keyDataType.String()}}
+
+	switch {
+	case cfg.hashSeed != nil:
+		impl.seeded, impl.k0, impl.k1 = true, *cfg.hashSeed, mixSeed(*cfg.hashSeed)
+	case cfg.randomHashSeed:
+		var seed [16]byte
+		if _, err := rand.Read(seed[:]); err != nil {
+			return nil, fmt.Errorf("unable to generate a random hash seed: %w", err)
+		}
+		impl.seeded = true
+		impl.k0 = binary.LittleEndian.Uint64(seed[:8])
+		impl.k1 = binary.LittleEndian.Uint64(seed[8:])
+	}
+	return impl, nil
+}
+
+// mixSeed derives the second half of the SipHash key from a 64 bits seed, using the splitmix64 finalizer.
+func mixSeed(seed uint64) uint64 {
+	seed += 0x9e3779b97f4a7c15
+	seed = (seed ^ (seed >> 30)) * 0xbf58476d1ce4e5b9
+	seed = (seed ^ (seed >> 27)) * 0x94d049bb133111eb
+	return seed ^ (seed >> 31)
+}
+
+// hashKeyToInt converts a given key of any comparable type into a hashed integer value.
+// The hash is computed over the concatenation of the key value and its data type,
+// as formatted by fmt with "%[1]v%[1]T", so keys with the same value but different data types,
+// e.g. 1 and "1", get different hashes. Supported primitive data types are formatted
+// without allocating, any other data type falls back to fmt.
+func (h *hashKeyToIntImpl[K]) hashKeyToInt(key K) int {
+	state := keyHash{fnv: fnvOffset32}
+	if h.seeded {
+		state.seeded = true
+		state.sip = newSipHash(h.k0, h.k1)
+	}
+
+	writeKey(&state, key, h.keyType)
+	return state.sum()
+}
+
+// keyHash accumulates the bytes of a key into FNV-1a or, if seeded, into SipHash-2-4.
+// It's a concrete type on purpose, so it doesn't escape to the heap.
+type keyHash struct {
+	fnv    uint32
+	sip    sipHash
+	seeded bool
+}
+
+func (h *keyHash) writeBytes(data []byte) {
+	if h.seeded {
+		h.sip.writeBytes(data)
+		return
+	}
+	h.fnv = fnvBytes(h.fnv, data)
+}
+
+func (h *keyHash) writeString(data string) {
+	if h.seeded {
+		h.sip.writeString(data)
+		return
+	}
+	h.fnv = fnvString(h.fnv, data)
+}
+
+// sum returns the hash of the written bytes as an int. The FNV-1a hash is negative on 32-bit platforms
+// when its highest bit is set.
+func (h *keyHash) sum() int {
+	if h.seeded {
+		return int(h.sip.sum64() & math.MaxInt)
+	}
+	return int(h.fnv)
+}
+
+// writeKey writes the key value and its data type into the provided keyHash.
+// keyDataType describes K, it's used for named types whose underlying type is primitive.
+func writeKey[K comparable](h *keyHash, key K, keyDataType keyType) {
+	var buffer [64]byte
+
+	switch k := any(key).(type) {
+	case string:
+		h.writeString(k)
+		h.writeString("string")
+	case bool:
+		h.writeBytes(strconv.AppendBool(buffer[:0], k))
+		h.writeString("bool")
+	case int:
+		h.writeBytes(strconv.AppendInt(buffer[:0], int64(k), 10))
+		h.writeString("int")
+	case int8:
+		h.writeBytes(strconv.AppendInt(buffer[:0], int64(k), 10))
+		h.writeString("int8")
+	case int16:
+		h.writeBytes(strconv.AppendInt(buffer[:0], int64(k), 10))
+		h.writeString("int16")
+	case int32:
+		h.writeBytes(strconv.AppendInt(buffer[:0], int64(k), 10))
+		h.writeString("int32")
+	case int64:
+		h.writeBytes(strconv.AppendInt(buffer[:0], k, 10))
+		h.writeString("int64")
+	case uint:
+		h.writeBytes(strconv.AppendUint(buffer[:0], uint64(k), 10))
+		h.writeString("uint")
+	case uint8:
+		h.writeBytes(strconv.AppendUint(buffer[:0], uint64(k), 10))
+		h.writeString("uint8")
+	case uint16:
+		h.writeBytes(strconv.AppendUint(buffer[:0], uint64(k), 10))
+		h.writeString("uint16")
+	case uint32:
+		h.writeBytes(strconv.AppendUint(buffer[:0], uint64(k), 10))
+		h.writeString("uint32")
+	case uint64:
+		h.writeBytes(strconv.AppendUint(buffer[:0], k, 10))
+		h.writeString("uint64")
+	case uintptr:
+		h.writeBytes(strconv.AppendUint(buffer[:0], uint64(k), 10))
+		h.writeString("uintptr")
+	case float32:
+		h.writeBytes(strconv.AppendFloat(buffer[:0], float64(k), 'g', -1, 32))
+		h.writeString("float32")
+	case float64:
+		h.writeBytes(strconv.AppendFloat(buffer[:0], k, 'g', -1, 64))
+		h.writeString("float64")
+	case complex64:
+		h.writeBytes(appendComplex(buffer[:0], complex128(k), 32))
+		h.writeString("complex64")
+	case complex128:
+		h.writeBytes(appendComplex(buffer[:0], k, 64))
+		h.writeString("complex128")
+	default:
+		if !writeNamedKey(h, buffer[:0], unsafe.Pointer(&key), keyDataType) {
+			h.writeBytes(appendValueAndType(buffer[:0], key))
+		}
+	}
+}
+
+// writeNamedKey writes the key pointed by key, whose data type is described by keyDataType,
+// reading it according to its kind instead of boxing it for reflection, so it doesn't allocate.
+// It returns false if the kind is not primitive or unknown, e.g. for the zero value of keyType.
+func writeNamedKey(h *keyHash, buffer []byte, key unsafe.Pointer, keyDataType keyType) bool {
+	switch keyDataType.kind {
+	case reflect.String:
+		h.writeString(*(*string)(key))
+	case reflect.Bool:
+		h.writeBytes(strconv.AppendBool(buffer, *(*bool)(key)))
+	case reflect.Int:
+		h.writeBytes(strconv.AppendInt(buffer, int64(*(*int)(key)), 10))
+	case reflect.Int8:
+		h.writeBytes(strconv.AppendInt(buffer, int64(*(*int8)(key)), 10))
+	case reflect.Int16:
+		h.writeBytes(strconv.AppendInt(buffer, int64(*(*int16)(key)), 10))
+	case reflect.Int32:
+		h.writeBytes(strconv.AppendInt(buffer, int64(*(*int32)(key)), 10))
+	case reflect.Int64:
+		h.writeBytes(strconv.AppendInt(buffer, *(*int64)(key), 10))
+	case reflect.Uint:
+		h.writeBytes(strconv.AppendUint(buffer, uint64(*(*uint)(key)), 10))
+	case reflect.Uint8:
+		h.writeBytes(strconv.AppendUint(buffer, uint64(*(*uint8)(key)), 10))
+	case reflect.Uint16:
+		h.writeBytes(strconv.AppendUint(buffer, uint64(*(*uint16)(key)), 10))
+	case reflect.Uint32:
+		h.writeBytes(strconv.AppendUint(buffer, uint64(*(*uint32)(key)), 10))
+	case reflect.Uint64:
+		h.writeBytes(strconv.AppendUint(buffer, *(*uint64)(key), 10))
+	case reflect.Uintptr:
+		h.writeBytes(strconv.AppendUint(buffer, uint64(*(*uintptr)(key)), 10))
+	case reflect.Float32:
+		h.writeBytes(strconv.AppendFloat(buffer, float64(*(*float32)(key)), 'g', -1, 32))
+	case reflect.Float64:
+		h.writeBytes(strconv.AppendFloat(buffer, *(*float64)(key), 'g', -1, 64))
+	case reflect.Complex64:
+		h.writeBytes(appendComplex(buffer, complex128(*(*complex64)(key)), 32))
+	case reflect.Complex128:
+		h.writeBytes(appendComplex(buffer, *(*complex128)(key), 64))
+	default:
+		return false
+	}
+	h.writeString(keyDataType.name)
+	return true
+}
+
+// appendValueAndType appends the key value and its data type as "%[1]v%[1]T" does.
+// It handles named types whose underlying type is primitive through reflection, and falls back to fmt otherwise.
+func appendValueAndType(dst []byte, key any) []byte {
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.String:
+		dst = append(dst, value.String()...)
+	case reflect.Bool:
+		dst = strconv.AppendBool(dst, value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		dst = strconv.AppendInt(dst, value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		dst = strconv.AppendUint(dst, value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		dst = strconv.AppendFloat(dst, value.Float(), 'g', -1, value.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		dst = appendComplex(dst, value.Complex(), value.Type().Bits()/2)
+	default:
+		return fmt.Appendf(dst, "%[1]v%[1]T", key)
+	}
+	return append(dst, value.Type().String()...)
+}
+
+// appendComplex appends a complex number as fmt formats it with %v, e.g. (1+2i).
+func appendComplex(dst []byte, value complex128, bitSize int) []byte {
+	dst = append(dst, '(')
+	dst = strconv.AppendFloat(dst, real(value), 'g', -1, bitSize)
+
+	// the imaginary part always has a sign
+	imaginaryStart := len(dst)
+	dst = strconv.AppendFloat(dst, imag(value), 'g', -1, bitSize)
+	if dst[imaginaryStart] != '+' && dst[imaginaryStart] != '-' {
+		dst = append(dst, 0)
+		copy(dst[imaginaryStart+1:], dst[imaginaryStart:])
+		dst[imaginaryStart] = '+'
+	}
+	return append(dst, "i)"...)
+}
+
+// fnvBytes applies FNV-1a to the provided bytes, starting from the provided hash.
+func fnvBytes(hash uint32, data []byte) uint32 {
+	for _, b := range data {
+		hash ^= uint32(b)
+		hash *= fnvPrime32
+	}
+	return hash
+}
+
+// fnvString applies FNV-1a to the provided string, starting from the provided hash.
+func fnvString(hash uint32, data string) uint32 {
+	for i := 0; i < len(data); i++ {
+		hash ^= uint32(data[i])
+		hash *= fnvPrime32
+	}
+	return hash
+}
@@ -0,0 +1,295 @@
+package cache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing allocation-free hashKeyToInt", hashCompatibilityTest)
+
+var _ = Describe("testing option WithHasher", withHasherTest)
+
+var _ = Describe("testing seeded hashing", seededHashTest)
+
+// fmtHashKeyToInt is the reference fmt based implementation, hashKeyToInt must return the same results
+func fmtHashKeyToInt(key any) int {
+	hasher := fnv.New32a()
+	hasher.Write([]byte(fmt.Sprintf("%[1]v%[1]T", key)))
+	return int(hasher.Sum32())
+}
+
+type temperature float64
+
+type coordinates complex64
+
+type enabled bool
+
+func hashCompatibilityTest() {
+	When("Comparing the results with the fmt based implementation", func() {
+		functionInvoker := new(hashKeyToIntImpl[any])
+		keys := []any{
+			"", "foo", "Foo", "ñandú",
+			true, false,
+			0, 1, -1, math.MaxInt, math.MinInt,
+			int8(math.MinInt8), int16(math.MaxInt16), int32(-123), int64(math.MinInt64),
+			uint(7), uint8(255), uint16(65535), uint32(math.MaxUint32), uint64(math.MaxUint64), uintptr(42),
+			'a', byte('a'),
+			float32(0.1), float32(-3.5e-12), float64(0.1), 1e21, 1e20, 123456789.0, 1e-7, -0.0,
+			math.Inf(1), math.Inf(-1), math.NaN(), float32(math.Inf(1)),
+			complex64(complex(1, 2)), complex(1.5, -2.25), complex(0, math.Inf(1)), complex(math.NaN(), math.NaN()),
+			complex(math.Inf(-1), math.Inf(-1)),
+			userID(1), tenantName("acme"), temperature(36.6), coordinates(complex(1, -1)), enabled(true),
+			struct{ a int }{1},
+		}
+
+		for _, key := range keys {
+			Context(fmt.Sprintf("Given %[1]v (%[1]T) as input", key), func() {
+				It("should return the same result", func() {
+					Expect(functionInvoker.hashKeyToInt(key)).Should(Equal(fmtHashKeyToInt(key)))
+				})
+			})
+		}
+	})
+
+	When("Comparing the results of named key types with the fmt based implementation", func() {
+		It("should return the same result when the key type is known in advance", func() {
+			userIDInvoker, err := newHashKeyToIntImpl[userID](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+			tenantInvoker, err := newHashKeyToIntImpl[tenantName](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+			temperatureInvoker, err := newHashKeyToIntImpl[temperature](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+			coordinatesInvoker, err := newHashKeyToIntImpl[coordinates](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+			enabledInvoker, err := newHashKeyToIntImpl[enabled](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(userIDInvoker.hashKeyToInt(-42)).Should(Equal(fmtHashKeyToInt(userID(-42))))
+			Expect(tenantInvoker.hashKeyToInt("acme")).Should(Equal(fmtHashKeyToInt(tenantName("acme"))))
+			Expect(temperatureInvoker.hashKeyToInt(36.6)).Should(Equal(fmtHashKeyToInt(temperature(36.6))))
+			Expect(coordinatesInvoker.hashKeyToInt(coordinates(complex(1, -1)))).Should(Equal(fmtHashKeyToInt(coordinates(complex(1, -1)))))
+			Expect(enabledInvoker.hashKeyToInt(true)).Should(Equal(fmtHashKeyToInt(enabled(true))))
+		})
+	})
+
+	When("Hashing supported primitive data types", func() {
+		It("should not allocate", func() {
+			intInvoker := new(hashKeyToIntImpl[int])
+			stringInvoker := new(hashKeyToIntImpl[string])
+			floatInvoker := new(hashKeyToIntImpl[float64])
+			complexInvoker := new(hashKeyToIntImpl[complex128])
+			userIDInvoker, err := newHashKeyToIntImpl[userID](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+			tenantInvoker, err := newHashKeyToIntImpl[tenantName](newConfig())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(testing.AllocsPerRun(100, func() {
+				intInvoker.hashKeyToInt(123456789)
+				stringInvoker.hashKeyToInt("tenant-42:user-1")
+				floatInvoker.hashKeyToInt(3.1416)
+				complexInvoker.hashKeyToInt(complex(1, -2))
+				userIDInvoker.hashKeyToInt(123456789)
+				tenantInvoker.hashKeyToInt("tenant-42")
+			})).Should(BeZero())
+		})
+	})
+
+	When("Getting a key that exists", func() {
+		It("should not allocate", func() {
+			cache, err := NewCacheWithGeometry[int, string](64, 8)
+			Expect(err).ShouldNot(HaveOccurred())
+			cache.Put(123456789, "foo")
+
+			Expect(testing.AllocsPerRun(100, func() {
+				cache.Get(123456789)
+			})).Should(BeZero())
+		})
+
+		It("should not allocate for a named key type", func() {
+			cache, err := NewCacheWithGeometry[userID, string](64, 8)
+			Expect(err).ShouldNot(HaveOccurred())
+			cache.Put(123456789, "foo")
+
+			Expect(testing.AllocsPerRun(100, func() {
+				cache.Get(123456789)
+			})).Should(BeZero())
+		})
+	})
+}
+
+type tenantKey struct {
+	tenant int
+	path   string
+}
+
+func withHasherTest() {
+	Context("Given a custom hasher", func() {
+		It("should use it to choose the set of every key", func() {
+			cache, err := NewCacheWithGeometry[int, string](4, 2, WithHasher(HasherFunc[int](func(key int) uint64 {
+				return uint64(key / 10)
+			})))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cache.Put(10, "ten")
+			cache.Put(11, "eleven")
+			cache.Put(21, "twenty one")
+			cache.Put(52, "fifty two")
+
+			// 10, 11 and 52 (52/10 % 4 sets) are mapped to set 1, which has 2 ways
+			Expect(cache.sets[1].items.Len()).Should(Equal(2))
+			Expect(cache.sets[2].items.Len()).Should(Equal(1))
+			Expect(cache.ListAll()).ShouldNot(HaveKey(10))
+		})
+	})
+
+	Context("Given a custom hasher returning values that overflow int", func() {
+		It("should always choose a valid set", func() {
+			cache, err := NewCacheWithGeometry[int, string](3, 2, WithHasher(HasherFunc[int](func(key int) uint64 {
+				return math.MaxUint64 - uint64(key)
+			})))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for key := 0; key < 10; key++ {
+				cache.Put(key, "value")
+				_, found := cache.Get(key)
+				Expect(found).Should(BeTrue())
+			}
+			Expect(cache.sets).Should(HaveLen(3))
+		})
+	})
+
+	Context("Given a struct key data type and a custom hasher", func() {
+		It("should not return an error and work as any other key", func() {
+			cache, err := NewCacheWithGeometry[tenantKey, string](8, 2, WithHasher(HasherFunc[tenantKey](func(key tenantKey) uint64 {
+				return uint64(key.tenant)*31 + uint64(len(key.path))
+			})))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cache.Put(tenantKey{42, "/home"}, "home")
+			value, found := cache.Get(tenantKey{42, "/home"})
+			Expect(found).Should(BeTrue())
+			Expect(value).Should(Equal("home"))
+
+			_, found = cache.Get(tenantKey{43, "/home"})
+			Expect(found).Should(BeFalse())
+		})
+	})
+
+	Context("Given a struct key data type without a custom hasher", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[tenantKey, string](8, 2)).Error().Should(HaveOccurred())
+		})
+	})
+
+	Context("Given a custom hasher with a different key data type", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[int, string](8, 2, WithHasher(HasherFunc[string](func(key string) uint64 {
+				return uint64(len(key))
+			})))).Error().Should(HaveOccurred())
+		})
+	})
+}
+
+func seededHashTest() {
+	Context("Given the SipHash-2-4 reference key 00..0f", func() {
+		It("should match the reference test vectors", func() {
+			sip := newSipHash(0x0706050403020100, 0x0f0e0d0c0b0a0908)
+			Expect(sip.sum64()).Should(BeEquivalentTo(uint64(0x726fdb47dd0e0e31)))
+
+			message := make([]byte, 15)
+			for i := range message {
+				message[i] = byte(i)
+			}
+			sip = newSipHash(0x0706050403020100, 0x0f0e0d0c0b0a0908)
+			sip.writeBytes(message[:3])
+			sip.writeString(string(message[3:11]))
+			sip.writeBytes(message[11:])
+			Expect(sip.sum64()).Should(BeEquivalentTo(uint64(0xa129ca6149be45e5)))
+		})
+	})
+
+	Context("Given the same explicit seed", func() {
+		It("should choose the same set for every key", func() {
+			first, err := NewCacheWithGeometry[string, int](64, 2, WithHashSeed(42))
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := NewCacheWithGeometry[string, int](64, 2, WithHashSeed(42))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("/users/%d", i)
+				Expect(first.hashKeyToIntConverter.hashKeyToInt(key)).Should(Equal(second.hashKeyToIntConverter.hashKeyToInt(key)))
+			}
+		})
+	})
+
+	Context("Given different seeds", func() {
+		It("should choose different sets", func() {
+			first := &hashKeyToIntImpl[string]{seeded: true, k0: 1, k1: mixSeed(1)}
+			second := &hashKeyToIntImpl[string]{seeded: true, k0: 2, k1: mixSeed(2)}
+
+			differences := 0
+			for i := 0; i < 100; i++ {
+				key := fmt.Sprintf("/users/%d", i)
+				if first.hashKeyToInt(key)%64 != second.hashKeyToInt(key)%64 {
+					differences++
+				}
+			}
+			Expect(differences).Should(BeNumerically(">", 50))
+		})
+	})
+
+	Context("Given keys crafted to land in the same set with the unseeded hash", func() {
+		It("should spread them across the sets", func() {
+			const numSets = 16
+			unseeded := new(hashKeyToIntImpl[string])
+			var keys []string
+			for i := 0; len(keys) < 64; i++ {
+				key := fmt.Sprintf("/flood/%d", i)
+				if unseeded.hashKeyToInt(key)%numSets == 0 {
+					keys = append(keys, key)
+				}
+			}
+
+			cache, err := NewCacheWithGeometry[string, int](numSets, 4, WithRandomHashSeed())
+			Expect(err).ShouldNot(HaveOccurred())
+			for i, key := range keys {
+				cache.Put(key, i)
+			}
+			usedSets := 0
+			for i := range cache.sets {
+				if cache.sets[i].len() > 0 {
+					usedSets++
+				}
+			}
+			Expect(usedSets).Should(BeNumerically(">", numSets/2))
+		})
+	})
+
+	Context("Given a seeded hash", func() {
+		It("should not allocate", func() {
+			intInvoker := &hashKeyToIntImpl[int]{seeded: true, k0: 1, k1: 2}
+			stringInvoker := &hashKeyToIntImpl[string]{seeded: true, k0: 1, k1: 2}
+			userIDInvoker, err := newHashKeyToIntImpl[userID](newConfig(WithHashSeed(1)))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(testing.AllocsPerRun(100, func() {
+				intInvoker.hashKeyToInt(123456789)
+				stringInvoker.hashKeyToInt("tenant-42:user-1")
+				userIDInvoker.hashKeyToInt(123456789)
+			})).Should(BeZero())
+		})
+	})
+
+	Context("Given a hash seed and a custom hasher", func() {
+		It("should return an error", func() {
+			hasher := WithHasher(HasherFunc[int](func(key int) uint64 { return uint64(key) }))
+			Expect(NewCacheWithGeometry[int, string](8, 2, hasher, WithHashSeed(1))).Error().Should(HaveOccurred())
+			Expect(NewCacheWithGeometry[int, string](8, 2, hasher, WithRandomHashSeed())).Error().Should(HaveOccurred())
+		})
+	})
+}
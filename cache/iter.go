@@ -0,0 +1,84 @@
+package cache
+
+import "iter"
+
+// All returns an iterator over the key-value pairs of the cache, expired entries are not included.
+// Pairs are yielded set by set and, within a set, from the most to the least recently used.
+// The cache is not copied as a whole: every set is copied when the iteration reaches it
+// and its pairs are yielded after releasing its lock, so the loop body can safely use the cache.
+// As a consequence, changes made during the iteration are only observed in the sets not reached yet.
+// Every key belongs to a single set, so a key is never yielded twice.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var buffer []entry[K, V]
+		for setIndex := range c.sets {
+			buffer = c.copySet(setIndex, buffer[:0])
+			for _, cachedEntry := range buffer {
+				if !yield(cachedEntry.key, cachedEntry.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the keys of the cache, expired entries are not included.
+// It follows the same order and has the same behaviour on changes during the iteration as All.
+func (c *Cache[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range c.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the values of the cache, expired entries are not included.
+// It follows the same order and has the same behaviour on changes during the iteration as All.
+func (c *Cache[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range c.All() {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// SetEntries returns an iterator over the key-value pairs of the provided set, from the most to the least
+// recently used, or from the newest to the oldest inserted for FIFO_ALGO and RANDOM_ALGO,
+// expired entries are not included. The set is copied when the iteration starts,
+// so changes made during the iteration are not observed. It yields nothing if setIndex is out of range.
+func (c *Cache[K, V]) SetEntries(setIndex int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if setIndex < 0 || setIndex >= len(c.sets) {
+			return
+		}
+		for _, cachedEntry := range c.copySet(setIndex, nil) {
+			if !yield(cachedEntry.key, cachedEntry.value) {
+				return
+			}
+		}
+	}
+}
+
+// copySet appends the entries of the provided set that are not expired to buffer, from the most to the least
+// recently used, and returns it. Hits recorded in the read buffer are applied first, so the order is up to date.
+func (c *Cache[K, V]) copySet(setIndex int, buffer []entry[K, V]) []entry[K, V] {
+	set := &c.sets[setIndex]
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if set.items == nil {
+		return buffer
+	}
+
+	set.applyReads()
+	for elem := set.items.Front(); elem != nil; elem = elem.Next() {
+		if cachedEntry := elem.Value.(*entry[K, V]); !cachedEntry.isExpired(c.now) {
+			buffer = append(buffer, *cachedEntry)
+		}
+	}
+	return buffer
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing iterators", func() {
+	Describe("testing function All", allTest)
+	Describe("testing function Keys", keysTest)
+	Describe("testing function Values", valuesTest)
+	Describe("testing function SetEntries", setEntriesTest)
+})
+
+func allTest() {
+	Context("Given entries in several sets", func() {
+		It("should yield them set by set, from the most to the least recently used", func() {
+			cacheTester, clock, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(1, "one")
+			cacheTester.Put(4, "four")
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(5, "five")
+			cacheTester.PutWithTTL(8, "eight", time.Second)
+			cacheTester.Get(4)
+			clock.advance(time.Second)
+
+			var keys []int
+			for key, value := range cacheTester.All() {
+				keys = append(keys, key)
+				Expect(value).Should(Equal(cacheTester.ListAll()[key]))
+			}
+			Expect(keys).Should(Equal([]int{4, 0, 5, 1}))
+			Expect(maps.Collect(cacheTester.All())).Should(Equal(cacheTester.ListAll()))
+		})
+	})
+
+	Context("Given the loop stops early", func() {
+		It("should stop yielding", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(2, "two")
+
+			count := 0
+			for range cacheTester.All() {
+				count++
+				break
+			}
+			Expect(count).Should(Equal(1))
+		})
+	})
+
+	Context("Given the cache is changed during the iteration", func() {
+		It("should not deadlock and observe the changes in the sets not reached yet", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+
+			var keys []int
+			for key := range cacheTester.All() {
+				keys = append(keys, key)
+				if key == 0 {
+					cacheTester.Delete(0)
+					cacheTester.Delete(1)
+					cacheTester.Put(4, "four")
+					cacheTester.Put(2, "two")
+				}
+			}
+			Expect(keys).Should(Equal([]int{0, 2}))
+		})
+	})
+}
+
+func keysTest() {
+	Context("Given entries in several sets", func() {
+		It("should yield every key", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(3, "three")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(7, "seven")
+
+			Expect(slices.Collect(cacheTester.Keys())).Should(Equal([]int{1, 7, 3}))
+		})
+	})
+}
+
+func valuesTest() {
+	Context("Given entries in several sets", func() {
+		It("should yield every value in the same order as Keys", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(3, "three")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(7, "seven")
+
+			Expect(slices.Collect(cacheTester.Values())).Should(Equal([]string{"one", "seven", "three"}))
+		})
+	})
+
+	Context("Given the loop stops early", func() {
+		It("should stop yielding", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+
+			count := 0
+			for range cacheTester.Values() {
+				count++
+				break
+			}
+			Expect(count).Should(Equal(1))
+		})
+	})
+}
+
+func setEntriesTest() {
+	Context("Given a set with several entries", func() {
+		It("should yield only the entries of the set in recency order", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			cacheTester.Put(1, "one")
+			cacheTester.Put(5, "five")
+			cacheTester.Put(9, "nine")
+			cacheTester.Put(2, "two")
+			cacheTester.Get(5)
+
+			var keys []int
+			var values []string
+			for key, value := range cacheTester.SetEntries(1) {
+				keys = append(keys, key)
+				values = append(values, value)
+			}
+			Expect(keys).Should(Equal([]int{5, 9, 1}))
+			Expect(values).Should(Equal([]string{"five", "nine", "one"}))
+		})
+	})
+
+	Context("Given hits recorded in the read buffer", func() {
+		It("should yield the entries in the up to date recency order", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3, WithReadBuffer(8))
+			cacheTester.Put(1, "one")
+			cacheTester.Put(5, "five")
+			cacheTester.Get(1)
+
+			var keys []int
+			for key := range cacheTester.SetEntries(1) {
+				keys = append(keys, key)
+			}
+			Expect(keys).Should(Equal([]int{1, 5}))
+		})
+	})
+
+	Context("Given an empty set or a set index out of range", func() {
+		It("should yield nothing", func() {
+			cacheTester, _, _ := newModuloCacheTester(4, 3)
+			Expect(maps.Collect(cacheTester.SetEntries(0))).Should(BeEmpty())
+			Expect(maps.Collect(cacheTester.SetEntries(-1))).Should(BeEmpty())
+			Expect(maps.Collect(cacheTester.SetEntries(4))).Should(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// janitor periodically removes expired entries from the cache.
+// Every pass scans at most setsPerPass sets, starting where the previous pass finished,
+// locking a single set at a time, so a big cache is never blocked for a full scan.
+type janitor struct {
+	interval    time.Duration
+	setsPerPass int
+	cursor      int
+	mutex       sync.Mutex
+	stop        chan struct{}
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// startJanitor launches the goroutine sweeping expired entries of the cache.
+func (c *Cache[K, V]) startJanitor(interval time.Duration, setsPerPass int) {
+	c.janitor = &janitor{
+		interval:    interval,
+		setsPerPass: setsPerPass,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go func(j *janitor) {
+		defer close(j.done)
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.sweepExpired()
+			case <-j.stop:
+				return
+			}
+		}
+	}(c.janitor)
+}
+
+// sweepExpired removes the expired entries of the next setsPerPass sets and returns how many entries were removed.
+func (c *Cache[K, V]) sweepExpired() int {
+	c.janitor.mutex.Lock()
+	defer c.janitor.mutex.Unlock()
+
+	removed := 0
+	setsToScan := min(c.janitor.setsPerPass, c.setSize)
+	for i := 0; i < setsToScan; i++ {
+		setIndex := c.janitor.cursor
+		c.janitor.cursor = (c.janitor.cursor + 1) % c.setSize
+		removed += c.sweepSet(setIndex)
+	}
+	return removed
+}
+
+// sweepSet removes the expired entries of the provided set and returns how many entries were removed.
+func (c *Cache[K, V]) sweepSet(setIndex int) int {
+	set := &c.sets[setIndex]
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	if set.items == nil {
+		return 0
+	}
+
+	removed := 0
+	for elem := set.items.Front(); elem != nil; {
+		next := elem.Next()
+		if cachedEntry := elem.Value.(*entry[K, V]); cachedEntry.isExpired(c.now) {
+			c.remove(setIndex, cachedEntry.key, EvictionReasonExpired)
+			removed++
+		}
+		elem = next
+	}
+	return removed
+}
+
+// Close stops the background janitor, if any, and waits until it finishes.
+// It's safe to invoke Close more than once. The cache can still be used after Close,
+// expired entries are then only removed when they are read.
+func (c *Cache[K, V]) Close() {
+	if c.janitor == nil {
+		return
+	}
+
+	c.janitor.closeOnce.Do(func() {
+		close(c.janitor.stop)
+	})
+	<-c.janitor.done
+}
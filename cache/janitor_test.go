@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing janitor", func() {
+	Describe("testing function sweepExpired", sweepExpiredTest)
+	Describe("testing option WithJanitor", withJanitorTest)
+})
+
+func sweepExpiredTest() {
+	var (
+		clock       *fakeClock
+		cacheTester *Cache[int, string]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		// a long interval, so the sweeps are driven by the test
+		cacheTester, err = NewCacheWithGeometry[int, string](4, 4, WithClock(clock.now), WithJanitor(time.Hour, 2))
+		Expect(err).ShouldNot(HaveOccurred())
+		DeferCleanup(cacheTester.Close)
+
+		mockedHashKeyToIntConverter := new(hashKeyToIntConverterMock[int])
+		cacheTester.hashKeyToIntConverter = mockedHashKeyToIntConverter
+		for key := 0; key < 8; key++ {
+			// two entries per set
+			mockedHashKeyToIntConverter.On("hashKeyToInt", key).Return(key % 4)
+		}
+		for key := 0; key < 8; key++ {
+			cacheTester.PutWithTTL(key, "expiring", time.Minute)
+		}
+		mockedHashKeyToIntConverter.On("hashKeyToInt", 100).Return(0)
+		cacheTester.Put(100, "forever")
+	})
+
+	Context("Given expired entries in every set", func() {
+		It("should only clean setsPerPass sets per pass, continuing from the previous pass", func() {
+			clock.advance(time.Minute)
+
+			// sets 0 and 1
+			Expect(cacheTester.sweepExpired()).Should(Equal(4))
+			Expect(cacheTester.sets[0].items.Len()).Should(Equal(1))
+			Expect(cacheTester.sets[1].items.Len()).Should(Equal(0))
+			Expect(cacheTester.sets[2].items.Len()).Should(Equal(2))
+			Expect(cacheTester.sets[3].items.Len()).Should(Equal(2))
+
+			// sets 2 and 3
+			Expect(cacheTester.sweepExpired()).Should(Equal(4))
+			Expect(cacheTester.sets[2].items.Len()).Should(Equal(0))
+			Expect(cacheTester.sets[3].items.Len()).Should(Equal(0))
+
+			// back to sets 0 and 1, nothing else to remove
+			Expect(cacheTester.sweepExpired()).Should(BeZero())
+			Expect(cacheTester.ListAll()).Should(HaveLen(1))
+			Expect(cacheTester.ListAll()).Should(HaveKey(100))
+		})
+	})
+
+	Context("Given entries that are not expired yet", func() {
+		It("should not remove them", func() {
+			Expect(cacheTester.sweepExpired()).Should(BeZero())
+			Expect(cacheTester.sweepExpired()).Should(BeZero())
+			Expect(cacheTester.ListAll()).Should(HaveLen(9))
+		})
+	})
+}
+
+func withJanitorTest() {
+	Context("Given a janitor running every millisecond", func() {
+		It("should eventually remove expired entries without reading them", func() {
+			clock := newFakeClock()
+			cacheTester, err := NewCacheWithGeometry[string, int](8, 2, WithJanitor(time.Millisecond, 3), WithClock(clock.now))
+			Expect(err).ShouldNot(HaveOccurred())
+			defer cacheTester.Close()
+
+			cacheTester.PutWithTTL("foo", 1, time.Minute)
+			cacheTester.Put("bar", 2)
+			clock.advance(time.Minute)
+
+			// Stats counts expired entries too, until they are removed
+			Eventually(func() int {
+				return cacheTester.Stats().Entries
+			}).Should(Equal(1))
+		})
+	})
+
+	Context("Given Close is invoked several times", func() {
+		It("should not panic", func() {
+			cacheTester, err := NewCacheWithGeometry[string, int](8, 2, WithJanitor(time.Millisecond, 3))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cacheTester.Close()
+			cacheTester.Close()
+		})
+	})
+
+	Context("Given a cache without janitor", func() {
+		It("Close should be a no-op", func() {
+			cacheTester, err := NewCacheWithGeometry[string, int](8, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cacheTester.janitor).Should(BeNil())
+			cacheTester.Close()
+		})
+	})
+
+	Context("Given a non positive interval or setsPerPass", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[string, int](8, 2, WithJanitor(0, 3))).Error().Should(HaveOccurred())
+			Expect(NewCacheWithGeometry[string, int](8, 2, WithJanitor(time.Second, 0))).Error().Should(HaveOccurred())
+			Expect(NewCacheWithGeometry[string, int](8, 2, WithJanitor(-time.Second, 3))).Error().Should(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// loadCall is an in-flight execution of a loader, shared by every caller looking for the same key.
+// cancelled is true if the loader failed because the ctx of the caller invoking it was done,
+// an error that must not be shared with the rest of callers.
+type loadCall[V any] struct {
+	done      chan struct{}
+	value     V
+	err       error
+	cancelled bool
+}
+
+// GetOrLoad returns the item associated to the provided key if it's present in cache.
+// Otherwise it invokes loader, saves the loaded value with Put and returns it.
+// Concurrent misses for the same key share a single in-flight load: only the first caller invokes loader
+// with its own ctx, the rest wait for its result or until their ctx is done.
+// If the load fails because the ctx of the first caller is done, the waiting callers whose ctx isn't
+// start a new load instead of receiving that error.
+// Errors returned by loader are not cached, the next call for the same key invokes loader again.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader func(ctx context.Context, key K) (V, error)) (V, error) {
+	for {
+		if value, found := c.Get(key); found {
+			return value, nil
+		}
+
+		c.loadMutex.Lock()
+		call, found := c.loads[key]
+		if !found {
+			break
+		}
+		c.loadMutex.Unlock()
+		select {
+		case <-call.done:
+			if !call.cancelled {
+				return call.value, call.err
+			}
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+
+	call := &loadCall[V]{done: make(chan struct{})}
+	if c.loads == nil {
+		c.loads = make(map[K]*loadCall[V])
+	}
+	c.loads[key] = call
+	c.loadMutex.Unlock()
+
+	c.load(ctx, key, loader, call)
+	return call.value, call.err
+}
+
+// load invokes loader and publishes its result to every caller waiting on call.
+// If loader panics the waiting callers receive an error and the panic is propagated to the caller of load.
+func (c *Cache[K, V]) load(ctx context.Context, key K, loader func(ctx context.Context, key K) (V, error), call *loadCall[V]) {
+	completed := false
+	defer func() {
+		if !completed {
+			call.err = fmt.Errorf("loader panicked for key '%v'", key)
+		}
+
+		c.loadMutex.Lock()
+		delete(c.loads, key)
+		c.loadMutex.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = loader(ctx, key)
+	if call.err == nil {
+		c.Put(key, call.value)
+	}
+	call.cancelled = call.err != nil && ctx.Err() != nil && errors.Is(call.err, ctx.Err())
+	completed = true
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing function GetOrLoad", getOrLoadTest)
+
+func getOrLoadTest() {
+	var cacheTester *Cache[string, int]
+
+	BeforeEach(func() {
+		var err error
+		cacheTester, err = NewCacheWithGeometry[string, int](4, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a key that exists", func() {
+		It("should return the cached value without invoking the loader", func() {
+			cacheTester.Put("foo", 1)
+
+			value, err := cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+				Fail("loader must not be invoked")
+				return 0, nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(Equal(1))
+		})
+	})
+
+	Context("Given a key that doesn't exist", func() {
+		It("should return the loaded value and save it in cache", func() {
+			value, err := cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+				return len(key), nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(Equal(3))
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"foo": 3}))
+		})
+	})
+
+	Context("Given a loader that fails", func() {
+		It("should return the error and not cache it", func() {
+			loaderErr := errors.New("backend unavailable")
+			invocations := 0
+			loader := func(ctx context.Context, key string) (int, error) {
+				invocations++
+				return 0, loaderErr
+			}
+
+			_, err := cacheTester.GetOrLoad(context.Background(), "foo", loader)
+			Expect(err).Should(MatchError(loaderErr))
+			_, err = cacheTester.GetOrLoad(context.Background(), "foo", loader)
+			Expect(err).Should(MatchError(loaderErr))
+
+			Expect(invocations).Should(Equal(2))
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+		})
+	})
+
+	Context("Given concurrent misses for the same key", func() {
+		It("should invoke the loader once and share the result", func() {
+			var invocations atomic.Int32
+			release := make(chan struct{})
+			loader := func(ctx context.Context, key string) (int, error) {
+				invocations.Add(1)
+				<-release
+				return 42, nil
+			}
+
+			var wg sync.WaitGroup
+			results := make([]int, 10)
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					value, err := cacheTester.GetOrLoad(context.Background(), "foo", loader)
+					Expect(err).ShouldNot(HaveOccurred())
+					results[i] = value
+				}(i)
+			}
+
+			Eventually(invocations.Load).Should(BeEquivalentTo(1))
+			Eventually(func() int {
+				cacheTester.loadMutex.Lock()
+				defer cacheTester.loadMutex.Unlock()
+				return len(cacheTester.loads)
+			}).Should(Equal(1))
+			close(release)
+			wg.Wait()
+
+			Expect(invocations.Load()).Should(BeEquivalentTo(1))
+			for _, result := range results {
+				Expect(result).Should(Equal(42))
+			}
+		})
+	})
+
+	Context("Given a waiting caller whose context is cancelled", func() {
+		It("should return the context error while the load keeps going", func() {
+			release := make(chan struct{})
+			loadDone := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(loadDone)
+				value, err := cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+					<-release
+					return 1, nil
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(value).Should(Equal(1))
+			}()
+
+			Eventually(func() int {
+				cacheTester.loadMutex.Lock()
+				defer cacheTester.loadMutex.Unlock()
+				return len(cacheTester.loads)
+			}).Should(Equal(1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := cacheTester.GetOrLoad(ctx, "foo", func(ctx context.Context, key string) (int, error) {
+				Fail("loader must not be invoked")
+				return 0, nil
+			})
+			Expect(err).Should(MatchError(context.Canceled))
+
+			close(release)
+			<-loadDone
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"foo": 1}))
+		})
+	})
+
+	Context("Given a load cancelled by the context of the caller invoking the loader", func() {
+		It("should load again for the waiting callers whose context isn't cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			leaderDone := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(leaderDone)
+				_, err := cacheTester.GetOrLoad(ctx, "foo", func(ctx context.Context, key string) (int, error) {
+					<-ctx.Done()
+					return 0, ctx.Err()
+				})
+				Expect(err).Should(MatchError(context.Canceled))
+			}()
+
+			Eventually(func() int {
+				cacheTester.loadMutex.Lock()
+				defer cacheTester.loadMutex.Unlock()
+				return len(cacheTester.loads)
+			}).Should(Equal(1))
+
+			type result struct {
+				value int
+				err   error
+			}
+			waiterDone := make(chan result, 1)
+			go func() {
+				value, err := cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+					return 2, nil
+				})
+				waiterDone <- result{value: value, err: err}
+			}()
+
+			// the waiter has missed the key, so it's waiting for the load of the first caller
+			Eventually(func() uint64 {
+				return cacheTester.Stats().Misses
+			}).Should(BeEquivalentTo(2))
+			cancel()
+			<-leaderDone
+
+			Expect(<-waiterDone).Should(Equal(result{value: 2}))
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"foo": 2}))
+		})
+	})
+
+	Context("Given a loader that panics", func() {
+		It("should propagate the panic and release the key for the next load", func() {
+			Expect(func() {
+				cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+					panic("boom")
+				})
+			}).Should(PanicWith("boom"))
+
+			value, err := cacheTester.GetOrLoad(context.Background(), "foo", func(ctx context.Context, key string) (int, error) {
+				return 2, nil
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(Equal(2))
+		})
+	})
+}
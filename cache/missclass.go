@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MissKind classifies a miss the way a hardware cache simulator does.
+type MissKind int
+
+const (
+	// CompulsoryMiss is the first reference to a key, no cache could have avoided it.
+	CompulsoryMiss MissKind = iota
+	// CapacityMiss would have happened even in a fully associative cache with the same capacity.
+	CapacityMiss
+	// ConflictMiss happened only because too many keys were mapped to the same set:
+	// a fully associative cache with the same capacity would have found the key.
+	ConflictMiss
+)
+
+// String returns a human readable representation of the miss kind.
+func (k MissKind) String() string {
+	switch k {
+	case CompulsoryMiss:
+		return "compulsory"
+	case CapacityMiss:
+		return "capacity"
+	case ConflictMiss:
+		return "conflict"
+	}
+	return "unknown"
+}
+
+// SetStats holds the counters of a single set, see Cache.SetStats.
+type SetStats struct {
+	// Hits is the number of Get invocations that found a key of the set.
+	Hits uint64
+	// Misses is the number of Get invocations that didn't find a key of the set.
+	Misses uint64
+	// Evictions is the number of entries removed from the set to make room for new ones.
+	Evictions uint64
+	// CompulsoryMisses, CapacityMisses and ConflictMisses classify Misses.
+	// They are only collected if the cache was created with WithMissClassification.
+	CompulsoryMisses uint64
+	CapacityMisses   uint64
+	ConflictMisses   uint64
+}
+
+// setCounters holds the counters of a single set. Its zero value is ready to use.
+type setCounters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	missKinds [ConflictMiss + 1]atomic.Uint64
+}
+
+func (s *setCounters) reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.evictions.Store(0)
+	for i := range s.missKinds {
+		s.missKinds[i].Store(0)
+	}
+}
+
+// seenCapacityFactor is the number of keys remembered by the miss classifier to tell capacity misses
+// from compulsory ones, as a multiple of the cache capacity.
+const seenCapacityFactor = 8
+
+// missClassifier simulates a fully associative LRU cache with the same capacity as the cache (the ghost),
+// fed with the same references. A missing key still present in the ghost is a conflict miss,
+// a missing key that was referenced before is a capacity miss, and any other miss is compulsory.
+// Only hits and writes are references: a miss alone doesn't make a key cacheable, so the misses
+// of a key that was never saved are always compulsory.
+// The keys referenced before are kept in another LRU (seen), bounded to seenCapacityFactor times
+// the capacity, so an unbounded key space doesn't grow it forever: a key referenced longer ago than that
+// is classified as compulsory.
+// It's shared by every set, so it has its own mutex, always acquired after the mutex of a set.
+type missClassifier[K comparable] struct {
+	capacity int
+	ghost    *recencyPolicy[K]
+	seen     *recencyPolicy[K]
+	mutex    sync.Mutex
+}
+
+func newMissClassifier[K comparable](capacity int) *missClassifier[K] {
+	return &missClassifier[K]{
+		capacity: capacity,
+		ghost:    newRecencyPolicy[K](false),
+		seen:     newRecencyPolicy[K](false),
+	}
+}
+
+// touch records a reference of the provided key in lru, evicting its least recently used key
+// if it holds more than capacity keys.
+func touch[K comparable](lru *recencyPolicy[K], key K, capacity int) {
+	if _, found := lru.items[key]; found {
+		lru.OnAccess(key)
+		return
+	}
+
+	lru.OnInsert(key)
+	if len(lru.items) > capacity {
+		if victim, ok := lru.Victim(); ok {
+			lru.OnRemove(victim)
+		}
+	}
+}
+
+// reference records a hit or a write of the provided key.
+func (m *missClassifier[K]) reference(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	touch(m.seen, key, m.capacity*seenCapacityFactor)
+	touch(m.ghost, key, m.capacity)
+}
+
+// classify returns the kind of the miss for the provided key.
+func (m *missClassifier[K]) classify(key K) MissKind {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	kind := CompulsoryMiss
+	if _, found := m.ghost.items[key]; found {
+		kind = ConflictMiss
+	} else if _, found := m.seen.items[key]; found {
+		kind = CapacityMiss
+	}
+	return kind
+}
+
+// forget drops every trace of the provided key, so its next miss is compulsory.
+// It's used for keys invalidated with Delete, expiration or Clear, which aren't misses caused by the cache geometry.
+func (m *missClassifier[K]) forget(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ghost.OnRemove(key)
+	m.seen.OnRemove(key)
+}
+
+// reset drops every reference recorded so far.
+func (m *missClassifier[K]) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.ghost = newRecencyPolicy[K](false)
+	m.seen = newRecencyPolicy[K](false)
+}
+
+// recordHit updates the set counters after a hit.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) recordHit(setIndex int, key K) {
+	c.setStats[setIndex].hits.Add(1)
+	c.recordReference(key)
+}
+
+// recordMiss updates the set counters after a miss, classifying it if enabled.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) recordMiss(setIndex int, key K) {
+	c.setStats[setIndex].misses.Add(1)
+	if c.missClassifier != nil {
+		c.setStats[setIndex].missKinds[c.missClassifier.classify(key)].Add(1)
+	}
+}
+
+// recordReference feeds the miss classifier, if enabled, with a hit or a write of the provided key.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) recordReference(key K) {
+	if c.missClassifier != nil {
+		c.missClassifier.reference(key)
+	}
+}
+
+// forgetReference drops the provided key from the miss classifier, if enabled.
+// It must be invoked while holding the mutex of the set.
+func (c *Cache[K, V]) forgetReference(key K) {
+	if c.missClassifier != nil {
+		c.missClassifier.forget(key)
+	}
+}
+
+// SetStats returns the counters of every set, indexed by set. It allows detecting hot sets,
+// and together with WithMissClassification, whether changing the geometry or the hashing
+// would improve the hit ratio more than growing the cache.
+func (c *Cache[K, V]) SetStats() []SetStats {
+	result := make([]SetStats, len(c.setStats))
+	for i := range c.setStats {
+		counters := &c.setStats[i]
+		result[i] = SetStats{
+			Hits:             counters.hits.Load(),
+			Misses:           counters.misses.Load(),
+			Evictions:        counters.evictions.Load(),
+			CompulsoryMisses: counters.missKinds[CompulsoryMiss].Load(),
+			CapacityMisses:   counters.missKinds[CapacityMiss].Load(),
+			ConflictMisses:   counters.missKinds[ConflictMiss].Load(),
+		}
+	}
+	return result
+}
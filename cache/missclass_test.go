@@ -0,0 +1,171 @@
+package cache
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing miss classification", func() {
+	Describe("testing option WithMissClassification", missClassificationTest)
+	Describe("testing bounded miss classifier", boundedMissClassifierTest)
+	Describe("testing function SetStats", setStatsTest)
+	Describe("testing MissKind", missKindTest)
+})
+
+func missClassificationTest() {
+	var (
+		mockedHashKeyToIntConverter *hashKeyToIntConverterMock[int]
+		cacheTester                 *Cache[int, string]
+	)
+
+	BeforeEach(func() {
+		var err error
+		// 2 sets x 2 ways = 4 entries
+		cacheTester, err = NewCacheWithGeometry[int, string](2, 2, WithMissClassification())
+		Expect(err).ShouldNot(HaveOccurred())
+
+		mockedHashKeyToIntConverter = new(hashKeyToIntConverterMock[int])
+		cacheTester.hashKeyToIntConverter = mockedHashKeyToIntConverter
+		for key := 0; key < 10; key++ {
+			// keys 0..4 are mapped to set 0, the rest to set 1
+			mockedHashKeyToIntConverter.On("hashKeyToInt", key).Return(key / 5)
+		}
+	})
+
+	Context("Given a key that was never referenced", func() {
+		It("should classify the miss as compulsory", func() {
+			cacheTester.Get(1)
+
+			stats := cacheTester.Stats()
+			Expect(stats.MissKinds[CompulsoryMiss]).Should(BeEquivalentTo(1))
+			Expect(cacheTester.SetStats()[0].CompulsoryMisses).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given repeated misses of a key that was never saved", func() {
+		It("should classify every miss as compulsory", func() {
+			cacheTester.Get(1)
+			cacheTester.Get(1)
+			cacheTester.Get(1)
+
+			stats := cacheTester.Stats()
+			Expect(stats.MissKinds[CompulsoryMiss]).Should(BeEquivalentTo(3))
+			Expect(stats.MissKinds[CapacityMiss]).Should(BeZero())
+			Expect(stats.MissKinds[ConflictMiss]).Should(BeZero())
+		})
+	})
+
+	Context("Given 3 hot keys in a 2-way set while the other set is empty", func() {
+		It("should classify the misses as conflict", func() {
+			// capacity is 4, but set 0 only holds 2 of them
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(2, "two") // 0 is evicted
+
+			_, found := cacheTester.Get(0)
+			Expect(found).Should(BeFalse())
+
+			stats := cacheTester.Stats()
+			Expect(stats.MissKinds[ConflictMiss]).Should(BeEquivalentTo(1))
+			Expect(stats.MissKinds[CapacityMiss]).Should(BeZero())
+			Expect(cacheTester.SetStats()[0].ConflictMisses).Should(BeEquivalentTo(1))
+			Expect(cacheTester.SetStats()[1].Misses).Should(BeZero())
+		})
+	})
+
+	Context("Given more keys than the total capacity", func() {
+		It("should classify the misses as capacity", func() {
+			for _, key := range []int{0, 1, 5, 6, 2, 7} {
+				cacheTester.Put(key, "value")
+			}
+
+			// 0 is the least recently used key of the 6 referenced keys, a fully associative cache of 4 lost it as well
+			_, found := cacheTester.Get(0)
+			Expect(found).Should(BeFalse())
+
+			stats := cacheTester.Stats()
+			Expect(stats.MissKinds[CapacityMiss]).Should(BeEquivalentTo(1))
+			Expect(stats.MissKinds[ConflictMiss]).Should(BeZero())
+		})
+	})
+
+	Context("Given a deleted key", func() {
+		It("should classify its next miss as compulsory", func() {
+			cacheTester.Put(0, "zero")
+			cacheTester.Delete(0)
+			cacheTester.Get(0)
+
+			Expect(cacheTester.Stats().MissKinds[CompulsoryMiss]).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given the cache is cleared", func() {
+		It("should classify the next misses as compulsory", func() {
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+			cacheTester.Put(2, "two")
+			cacheTester.Clear()
+			cacheTester.Get(0)
+			cacheTester.Get(1)
+
+			Expect(cacheTester.Stats().MissKinds[CompulsoryMiss]).Should(BeEquivalentTo(2))
+		})
+	})
+}
+
+func boundedMissClassifierTest() {
+	Context("Given more distinct keys than the classifier remembers", func() {
+		It("should keep a bounded number of keys and classify the oldest ones as compulsory", func() {
+			classifier := newMissClassifier[int](2)
+			for key := 0; key < 100; key++ {
+				classifier.reference(key)
+			}
+
+			Expect(classifier.ghost.items).Should(HaveLen(2))
+			Expect(classifier.seen.items).Should(HaveLen(2 * seenCapacityFactor))
+			Expect(classifier.classify(0)).Should(Equal(CompulsoryMiss))
+			Expect(classifier.classify(90)).Should(Equal(CapacityMiss))
+			Expect(classifier.classify(99)).Should(Equal(ConflictMiss))
+		})
+	})
+}
+
+func setStatsTest() {
+	Context("Given a cache without miss classification", func() {
+		It("should count hits, misses and evictions per set", func() {
+			cacheTester, err := NewCacheWithGeometry[int, string](2, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			mockedHashKeyToIntConverter := new(hashKeyToIntConverterMock[int])
+			cacheTester.hashKeyToIntConverter = mockedHashKeyToIntConverter
+			mockedHashKeyToIntConverter.On("hashKeyToInt", 1).Return(1)
+			mockedHashKeyToIntConverter.On("hashKeyToInt", 3).Return(1)
+			mockedHashKeyToIntConverter.On("hashKeyToInt", 2).Return(0)
+
+			cacheTester.Put(1, "one")
+			cacheTester.Put(3, "three") // 1 is evicted
+			cacheTester.Get(3)
+			cacheTester.Get(1)
+			cacheTester.Get(2)
+
+			Expect(cacheTester.SetStats()).Should(Equal([]SetStats{
+				{Misses: 1},
+				{Hits: 1, Misses: 1, Evictions: 1},
+			}))
+			Expect(cacheTester.Stats().MissKinds).Should(BeEmpty())
+
+			cacheTester.ResetStats()
+			Expect(cacheTester.SetStats()).Should(Equal([]SetStats{{}, {}}))
+		})
+	})
+}
+
+func missKindTest() {
+	Context("Given every miss kind", func() {
+		It("should return a readable name", func() {
+			Expect(CompulsoryMiss.String()).Should(Equal("compulsory"))
+			Expect(CapacityMiss.String()).Should(Equal("capacity"))
+			Expect(ConflictMiss.String()).Should(Equal("conflict"))
+			Expect(MissKind(100).String()).Should(Equal("unknown"))
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing functions Len and SetOccupancy", occupancyTest)
+
+func occupancyTest() {
+	var (
+		clock       *fakeClock
+		cacheTester *Cache[int, string]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		cacheTester, err = NewCacheWithGeometry[int, string](4, 2, WithClock(clock.now), WithHasher(HasherFunc[int](func(key int) uint64 {
+			return uint64(key)
+		})))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a new cache", func() {
+		It("should be empty", func() {
+			Expect(cacheTester.Len()).Should(BeZero())
+			Expect(cacheTester.Cap()).Should(Equal(8))
+			Expect(cacheTester.SetOccupancy()).Should(Equal([]int{0, 0, 0, 0}))
+		})
+	})
+
+	Context("Given insertions, updates, evictions and deletes", func() {
+		It("should count the entries of every set", func() {
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(4, "four")
+			cacheTester.Put(8, "eight") // set 0 is full, 0 is evicted
+			cacheTester.Put(1, "one")
+			cacheTester.Put(1, "uno") // update
+			cacheTester.Put(2, "two")
+			cacheTester.Delete(2)
+
+			Expect(cacheTester.Len()).Should(Equal(3))
+			Expect(cacheTester.SetOccupancy()).Should(Equal([]int{2, 1, 0, 0}))
+		})
+	})
+
+	Context("Given expired entries", func() {
+		It("should count them until they are removed", func() {
+			cacheTester.PutWithTTL(3, "three", time.Second)
+			clock.advance(time.Second)
+			Expect(cacheTester.Len()).Should(Equal(1))
+			Expect(cacheTester.SetOccupancy()).Should(Equal([]int{0, 0, 0, 1}))
+
+			cacheTester.Get(3)
+			Expect(cacheTester.Len()).Should(BeZero())
+			Expect(cacheTester.SetOccupancy()).Should(Equal([]int{0, 0, 0, 0}))
+		})
+	})
+
+	Context("Given Clear is invoked", func() {
+		It("should be empty", func() {
+			cacheTester.Put(0, "zero")
+			cacheTester.Put(1, "one")
+			cacheTester.Clear()
+
+			Expect(cacheTester.Len()).Should(BeZero())
+			Expect(cacheTester.SetOccupancy()).Should(Equal([]int{0, 0, 0, 0}))
+		})
+	})
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Option configures optional behaviour of a Cache at construction time.
+type Option func(*config)
+
+// config holds the optional settings collected from the provided options.
+type config struct {
+	numSets              int
+	ways                 int
+	replacementAlgorithm ReplacementAlgo
+	evictionPolicy       any
+	defaultTTL           time.Duration
+	clock                func() time.Time
+	janitorInterval      time.Duration
+	janitorSetsPerPass   int
+	onEvict              any
+	missClassification   bool
+	hasher               any
+	hashSeed             *uint64
+	randomHashSeed       bool
+	readBufferSize       int
+	rand                 *rand.Rand
+}
+
+// newConfig returns the default configuration with all provided options applied.
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		replacementAlgorithm: LRU_ALGO,
+		clock:                time.Now,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithSets defines the number of sets of the cache, it must be positive. Required by NewCacheWithOptions.
+// Every key is mapped to a single set, so the more sets, the less keys compete for the same ways.
+func WithSets(numSets int) Option {
+	return func(cfg *config) {
+		cfg.numSets = numSets
+	}
+}
+
+// WithWays defines the number of ways, that is entries, of every set, it must be positive.
+// Required by NewCacheWithOptions.
+func WithWays(ways int) Option {
+	return func(cfg *config) {
+		cfg.ways = ways
+	}
+}
+
+// WithReplacementAlgo defines the algorithm used to choose which entry is removed when a set is full.
+// LRU is used by default, and by the empty algorithm, e.g. a field omitted in a configuration file.
+// Otherwise it must be one of the ReplacementAlgo constants, any other value is rejected with ErrUnknownReplacementAlgo.
+func WithReplacementAlgo(replacementAlgorithm ReplacementAlgo) Option {
+	return func(cfg *config) {
+		cfg.replacementAlgorithm = replacementAlgorithm
+	}
+}
+
+// WithEvictionPolicy defines a custom EvictionPolicy. The provided constructor is invoked once per set,
+// so every set gets its own policy instance. It takes precedence over WithReplacementAlgo.
+// The built-in policies can be used as well, e.g.:
+//   - cache.WithEvictionPolicy(cache.NewLFUPolicy[int])
+func WithEvictionPolicy[K comparable](newPolicy func() EvictionPolicy[K]) Option {
+	return func(cfg *config) {
+		cfg.evictionPolicy = newPolicy
+	}
+}
+
+// WithDefaultTTL defines the time to live applied to the entries saved with Put.
+// By default entries never expire.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(cfg *config) {
+		cfg.defaultTTL = ttl
+	}
+}
+
+// WithClock defines the function used to get the current time when computing and checking expirations.
+// time.Now is used by default or if nil is provided, a custom clock is mostly useful in tests.
+func WithClock(now func() time.Time) Option {
+	return func(cfg *config) {
+		if now != nil {
+			cfg.clock = now
+		}
+	}
+}
+
+// WithJanitor starts a background goroutine that removes expired entries every interval.
+// Every pass scans at most setsPerPass sets, continuing from where the previous pass stopped,
+// so the cache is never locked for a full scan. Both values must be positive.
+// Close must be invoked to stop the janitor once the cache is no longer needed.
+func WithJanitor(interval time.Duration, setsPerPass int) Option {
+	return func(cfg *config) {
+		cfg.janitorInterval = interval
+		cfg.janitorSetsPerPass = setsPerPass
+	}
+}
+
+// OnEvict defines a listener invoked every time an entry leaves the cache, together with the reason.
+// The listener is invoked after the lock of the set is released, so it can safely invoke the cache again.
+// With EvictionReasonReplaced it receives the previous value of the key, which may be the very same value
+// saved again, e.g. the same pointer, so a listener releasing resources must check the value isn't
+// still cached before releasing it.
+func OnEvict[K comparable, V any](listener func(key K, value V, reason EvictionReason)) Option {
+	return func(cfg *config) {
+		cfg.onEvict = listener
+	}
+}
+
+// WithMissClassification enables the classification of every miss as compulsory, capacity or conflict,
+// reported by Stats and SetStats. It keeps a fully associative shadow of the cache and the last
+// 8 * Cap() distinct keys saved or hit, so memory usage is bounded but up to 9 times the number of keys
+// of the cache, and it's mostly meant to tune the cache geometry. A key referenced longer ago than
+// the last 8 * Cap() distinct keys is classified as compulsory, and so is every miss of a key that was never saved.
+func WithMissClassification() Option {
+	return func(cfg *config) {
+		cfg.missClassification = true
+	}
+}
+
+// WithHasher defines the Hasher used to choose the set of every key, instead of the built-in FNV-1a based one.
+// When a custom Hasher is provided, any comparable data type can be used as key, e.g. structs.
+func WithHasher[K comparable](hasher Hasher[K]) Option {
+	return func(cfg *config) {
+		cfg.hasher = hasher
+	}
+}
+
+// WithHashSeed mixes the provided seed into the built-in hash, which switches to SipHash-2-4,
+// so the set of every key can't be predicted without knowing the seed.
+// The same seed always produces the same distribution, which is useful in tests and snapshots.
+func WithHashSeed(seed uint64) Option {
+	return func(cfg *config) {
+		cfg.hashSeed = &seed
+	}
+}
+
+// WithRandomHashSeed mixes a random seed, generated once per cache, into the built-in hash,
+// which switches to SipHash-2-4. It prevents an attacker who controls the keys from crafting
+// keys that all land in the same set and evict each other.
+func WithRandomHashSeed() Option {
+	return func(cfg *config) {
+		cfg.randomHashSeed = true
+	}
+}
+
+// WithReadBuffer makes Get look keys up under a shared lock, so readers of the same set don't block each other.
+// Instead of reordering the set on every hit, Get records the access in a buffer of size entries per set,
+// applied in batches when it's full or before the next write to the set. The buffer is split in stripes,
+// one per processor up to size, and every hit picks a random stripe, so readers of the same set rarely
+// contend on the same one. A hit is dropped when its stripe is in use by another reader, or full and
+// being applied by another goroutine, so the LRU, MRU and LFU orders become approximate in exchange
+// for read throughput. size must be positive.
+// It's ignored by FIFO_ALGO and RANDOM_ALGO, whose Get always takes the shared lock only.
+func WithReadBuffer(size int) Option {
+	return func(cfg *config) {
+		cfg.readBufferSize = size
+	}
+}
+
+// WithRand defines the random generator used by RANDOM_ALGO to choose the victim of a full set,
+// e.g. rand.New(rand.NewPCG(1, 2)) to get reproducible simulations. Sets evict concurrently,
+// so the cache serializes the access to the generator. It's ignored by any other algorithm,
+// so the algorithm can be switched without changing the rest of the options.
+// The global generator of math/rand/v2 is used by default.
+func WithRand(rng *rand.Rand) Option {
+	return func(cfg *config) {
+		cfg.rand = rng
+	}
+}
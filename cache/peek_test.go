@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/azlancpool/mycacheengine/cache/cacheiface"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ cacheiface.PeekableCache[string, int] = (*Cache[string, int])(nil)
+
+var _ = Describe("testing functions Peek and Contains", peekTest)
+
+func peekTest() {
+	var (
+		clock       *fakeClock
+		cacheTester *Cache[string, int]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		// a single set with 2 ways, so every key competes for the same slots
+		cacheTester, err = NewCacheWithGeometry[string, int](1, 2, WithClock(clock.now))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a key that exists", func() {
+		It("should return its value without promoting it", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+
+			value, found := cacheTester.Peek("a")
+			Expect(found).Should(BeTrue())
+			Expect(value).Should(Equal(1))
+			Expect(cacheTester.Contains("a")).Should(BeTrue())
+
+			// "a" is still the least recently used key
+			cacheTester.Put("c", 3)
+			Expect(cacheTester.Contains("a")).Should(BeFalse())
+			Expect(cacheTester.Contains("b")).Should(BeTrue())
+		})
+	})
+
+	Context("Given a key that doesn't exist", func() {
+		It("should return false", func() {
+			value, found := cacheTester.Peek("missing")
+			Expect(found).Should(BeFalse())
+			Expect(value).Should(BeZero())
+			Expect(cacheTester.Contains("missing")).Should(BeFalse())
+		})
+	})
+
+	Context("Given hits and misses through Peek and Contains", func() {
+		It("should not update the stats", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Peek("a")
+			cacheTester.Peek("missing")
+			cacheTester.Contains("a")
+			cacheTester.Contains("missing")
+
+			stats := cacheTester.Stats()
+			Expect(stats.Hits).Should(BeZero())
+			Expect(stats.Misses).Should(BeZero())
+			Expect(cacheTester.SetStats()[0]).Should(Equal(SetStats{}))
+		})
+	})
+
+	Context("Given an expired key", func() {
+		It("should report it as missing without removing it", func() {
+			cacheTester.PutWithTTL("a", 1, time.Second)
+			clock.advance(time.Second)
+
+			_, found := cacheTester.Peek("a")
+			Expect(found).Should(BeFalse())
+			Expect(cacheTester.Contains("a")).Should(BeFalse())
+			Expect(cacheTester.Len()).Should(Equal(1))
+		})
+	})
+}
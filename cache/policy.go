@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"container/list"
+	"math/rand/v2"
+	"sync"
+)
+
+// EvictionPolicy decides which entry of a set is removed when the set is full.
+// The cache creates a new policy per set and notifies it about every change of that set,
+// always while holding the lock of that set, so implementations don't need to be thread safe.
+type EvictionPolicy[K comparable] interface {
+	// OnInsert is invoked after a new key is added to the set.
+	OnInsert(key K)
+	// OnAccess is invoked after a key of the set is read.
+	OnAccess(key K)
+	// OnUpdate is invoked after the value of a key of the set is overwritten.
+	OnUpdate(key K)
+	// OnRemove is invoked after a key leaves the set, either because it was evicted or deleted.
+	OnRemove(key K)
+	// Victim returns the key that must be evicted from the set. It returns false if there is nothing to evict.
+	// If it returns false or a key that isn't in the set while the set is full, the cache evicts
+	// the entry at the back of the set instead, so a set never holds more keys than its ways.
+	Victim() (K, bool)
+}
+
+// PolicyConstructor returns a new EvictionPolicy for a set. It allows providing a custom policy
+// to NewCache, e.g. cache.PolicyConstructor[int](cache.NewLFUPolicy[int]).
+type PolicyConstructor[K comparable] func() EvictionPolicy[K]
+
+// option returns the Option configuring the policy, see WithEvictionPolicy.
+func (p PolicyConstructor[K]) option() Option {
+	return WithEvictionPolicy[K](p)
+}
+
+// NewLRUPolicy returns an EvictionPolicy that evicts the least recently used key of the set.
+func NewLRUPolicy[K comparable]() EvictionPolicy[K] {
+	return newRecencyPolicy[K](false)
+}
+
+// NewMRUPolicy returns an EvictionPolicy that evicts the most recently used key of the set.
+func NewMRUPolicy[K comparable]() EvictionPolicy[K] {
+	return newRecencyPolicy[K](true)
+}
+
+// NewLFUPolicy returns an EvictionPolicy that evicts the least frequently used key of the set.
+// Ties are broken by recency: among the keys with the lowest frequency, the least recently used one is evicted.
+func NewLFUPolicy[K comparable]() EvictionPolicy[K] {
+	return &lfuPolicy[K]{
+		frequencies: list.New(),
+		items:       make(map[K]*list.Element),
+	}
+}
+
+// NewFIFOPolicy returns an EvictionPolicy that evicts the oldest inserted key of the set.
+// Reads and updates don't change the order of the keys.
+func NewFIFOPolicy[K comparable]() EvictionPolicy[K] {
+	return fifoPolicy[K]{newRecencyPolicy[K](false)}
+}
+
+// NewRandomPolicy returns an EvictionPolicy that evicts a uniformly random key of the set,
+// chosen with the global generator of math/rand/v2.
+func NewRandomPolicy[K comparable]() EvictionPolicy[K] {
+	return newRandomPolicy[K](rand.IntN)
+}
+
+// policyFactory returns the constructor of the built-in EvictionPolicy matching the provided algorithm.
+// intN is the random generator used by RANDOM_ALGO.
+func policyFactory[K comparable](replacementAlgorithm ReplacementAlgo, intN func(n int) int) func() EvictionPolicy[K] {
+	switch replacementAlgorithm {
+	case MRU_ALGO:
+		return NewMRUPolicy[K]
+	case LFU_ALGO:
+		return NewLFUPolicy[K]
+	case FIFO_ALGO:
+		return NewFIFOPolicy[K]
+	case RANDOM_ALGO:
+		return func() EvictionPolicy[K] { return newRandomPolicy[K](intN) }
+	}
+	return NewLRUPolicy[K]
+}
+
+// recencyPolicy keeps the keys of a set sorted by recency, the most recently used key is at the front.
+// It implements LRU (evicts the back) and MRU (evicts the front).
+type recencyPolicy[K comparable] struct {
+	order      *list.List
+	items      map[K]*list.Element
+	mostRecent bool
+}
+
+func newRecencyPolicy[K comparable](mostRecent bool) *recencyPolicy[K] {
+	return &recencyPolicy[K]{
+		order:      list.New(),
+		items:      make(map[K]*list.Element),
+		mostRecent: mostRecent,
+	}
+}
+
+func (p *recencyPolicy[K]) OnInsert(key K) {
+	p.items[key] = p.order.PushFront(key)
+}
+
+func (p *recencyPolicy[K]) OnAccess(key K) {
+	if elem, found := p.items[key]; found {
+		p.order.MoveToFront(elem)
+	}
+}
+
+func (p *recencyPolicy[K]) OnUpdate(key K) {
+	p.OnAccess(key)
+}
+
+func (p *recencyPolicy[K]) OnRemove(key K) {
+	if elem, found := p.items[key]; found {
+		p.order.Remove(elem)
+		delete(p.items, key)
+	}
+}
+
+func (p *recencyPolicy[K]) Victim() (K, bool) {
+	elem := p.order.Back()
+	if p.mostRecent {
+		elem = p.order.Front()
+	}
+
+	if elem == nil {
+		var zero K
+		return zero, false
+	}
+	return elem.Value.(K), true
+}
+
+// lfuPolicy implements an O(1) LFU. frequencies holds one frequencyBucket per distinct frequency,
+// sorted in ascending order, and every bucket keeps its keys sorted by recency.
+type lfuPolicy[K comparable] struct {
+	frequencies *list.List
+	items       map[K]*list.Element
+}
+
+type frequencyBucket[K comparable] struct {
+	frequency int
+	keys      *list.List
+}
+
+type lfuItem[K comparable] struct {
+	key    K
+	bucket *list.Element
+}
+
+func (p *lfuPolicy[K]) OnInsert(key K) {
+	bucket := p.frequencies.Front()
+	if bucket == nil || bucket.Value.(*frequencyBucket[K]).frequency != 1 {
+		bucket = p.frequencies.PushFront(&frequencyBucket[K]{frequency: 1, keys: list.New()})
+	}
+	p.items[key] = bucket.Value.(*frequencyBucket[K]).keys.PushFront(&lfuItem[K]{key: key, bucket: bucket})
+}
+
+func (p *lfuPolicy[K]) OnAccess(key K) {
+	elem, found := p.items[key]
+	if !found {
+		return
+	}
+
+	item := elem.Value.(*lfuItem[K])
+	current := item.bucket
+	currentBucket := current.Value.(*frequencyBucket[K])
+
+	next := current.Next()
+	if next == nil || next.Value.(*frequencyBucket[K]).frequency != currentBucket.frequency+1 {
+		next = p.frequencies.InsertAfter(&frequencyBucket[K]{frequency: currentBucket.frequency + 1, keys: list.New()}, current)
+	}
+
+	currentBucket.keys.Remove(elem)
+	if currentBucket.keys.Len() == 0 {
+		p.frequencies.Remove(current)
+	}
+
+	item.bucket = next
+	p.items[key] = next.Value.(*frequencyBucket[K]).keys.PushFront(item)
+}
+
+func (p *lfuPolicy[K]) OnUpdate(key K) {
+	p.OnAccess(key)
+}
+
+func (p *lfuPolicy[K]) OnRemove(key K) {
+	elem, found := p.items[key]
+	if !found {
+		return
+	}
+
+	bucket := elem.Value.(*lfuItem[K]).bucket
+	bucket.Value.(*frequencyBucket[K]).keys.Remove(elem)
+	if bucket.Value.(*frequencyBucket[K]).keys.Len() == 0 {
+		p.frequencies.Remove(bucket)
+	}
+	delete(p.items, key)
+}
+
+func (p *lfuPolicy[K]) Victim() (K, bool) {
+	bucket := p.frequencies.Front()
+	if bucket == nil {
+		var zero K
+		return zero, false
+	}
+	return bucket.Value.(*frequencyBucket[K]).keys.Back().Value.(*lfuItem[K]).key, true
+}
+
+// fifoPolicy keeps the keys of a set sorted by insertion, ignoring reads and updates,
+// so the victim is the oldest inserted key.
+type fifoPolicy[K comparable] struct {
+	*recencyPolicy[K]
+}
+
+func (p fifoPolicy[K]) OnAccess(key K) {}
+
+func (p fifoPolicy[K]) OnUpdate(key K) {}
+
+// randomPolicy keeps the keys of a set in a slice, so a random victim is chosen in O(1),
+// and the position of every key, so a key is removed in O(1) by moving the last key to its position.
+type randomPolicy[K comparable] struct {
+	keys      []K
+	positions map[K]int
+	intN      func(n int) int
+}
+
+func newRandomPolicy[K comparable](intN func(n int) int) *randomPolicy[K] {
+	return &randomPolicy[K]{
+		positions: make(map[K]int),
+		intN:      intN,
+	}
+}
+
+func (p *randomPolicy[K]) OnInsert(key K) {
+	p.positions[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+func (p *randomPolicy[K]) OnAccess(key K) {}
+
+func (p *randomPolicy[K]) OnUpdate(key K) {}
+
+func (p *randomPolicy[K]) OnRemove(key K) {
+	position, found := p.positions[key]
+	if !found {
+		return
+	}
+
+	last := len(p.keys) - 1
+	p.keys[position] = p.keys[last]
+	p.positions[p.keys[position]] = position
+	var zero K
+	p.keys[last] = zero
+	p.keys = p.keys[:last]
+	delete(p.positions, key)
+}
+
+func (p *randomPolicy[K]) Victim() (K, bool) {
+	if len(p.keys) == 0 {
+		var zero K
+		return zero, false
+	}
+	return p.keys[p.intN(len(p.keys))], true
+}
+
+// lockedRand serializes the access to a random generator shared by the policies of every set,
+// since the sets evict concurrently and rand.Rand is not thread safe.
+type lockedRand struct {
+	mutex sync.Mutex
+	rng   *rand.Rand
+}
+
+func (r *lockedRand) IntN(n int) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rng.IntN(n)
+}
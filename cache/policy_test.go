@@ -0,0 +1,383 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing eviction policies", func() {
+	Describe("testing LRU policy", lruPolicyTest)
+	Describe("testing MRU policy", mruPolicyTest)
+	Describe("testing LFU policy", lfuPolicyTest)
+	Describe("testing FIFO policy", fifoPolicyTest)
+	Describe("testing Random policy", randomPolicyTest)
+	Describe("testing custom policy", customPolicyTest)
+})
+
+func lruPolicyTest() {
+	Context("Given an empty policy", func() {
+		It("should not return a victim", func() {
+			_, ok := NewLRUPolicy[int]().Victim()
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("Given keys [1,2,3] inserted and key 1 accessed", func() {
+		It("should return 2 as victim", func() {
+			policy := NewLRUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnAccess(1)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(2))
+		})
+	})
+
+	Context("Given keys [1,2,3] inserted and key 1 removed", func() {
+		It("should return 2 as victim", func() {
+			policy := NewLRUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnRemove(1)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(2))
+		})
+	})
+}
+
+func mruPolicyTest() {
+	Context("Given keys [1,2,3] inserted and key 1 updated", func() {
+		It("should return 1 as victim", func() {
+			policy := NewMRUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnUpdate(1)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(1))
+		})
+	})
+}
+
+func lfuPolicyTest() {
+	Context("Given an empty policy", func() {
+		It("should not return a victim", func() {
+			_, ok := NewLFUPolicy[int]().Victim()
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("Given keys with different frequencies", func() {
+		It("should return the least frequently used key", func() {
+			policy := NewLFUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnAccess(1)
+			policy.OnAccess(1)
+			policy.OnAccess(3)
+			policy.OnUpdate(2)
+			policy.OnAccess(2)
+
+			// frequencies: 1=3, 2=3, 3=2
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(3))
+		})
+	})
+
+	Context("Given keys with the same frequency", func() {
+		It("should return the least recently used key", func() {
+			policy := NewLFUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnAccess(2)
+			policy.OnAccess(1)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(2))
+		})
+	})
+
+	Context("Given the least frequently used key is removed", func() {
+		It("should return the next least frequently used key", func() {
+			policy := NewLFUPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnAccess(2)
+			policy.OnAccess(2)
+			policy.OnAccess(3)
+			policy.OnRemove(1)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(3))
+
+			policy.OnRemove(3)
+			policy.OnRemove(2)
+			_, ok = policy.Victim()
+			Expect(ok).Should(BeFalse())
+		})
+	})
+}
+
+func fifoPolicyTest() {
+	Context("Given keys [1,2,3] inserted and keys 1 and 2 accessed and updated", func() {
+		It("should return 1 as victim", func() {
+			policy := NewFIFOPolicy[int]()
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnAccess(1)
+			policy.OnUpdate(2)
+
+			victim, ok := policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(1))
+
+			policy.OnRemove(1)
+			victim, ok = policy.Victim()
+			Expect(ok).Should(BeTrue())
+			Expect(victim).Should(Equal(2))
+		})
+	})
+
+	Context("Given a FIFO cache with 1 set and 3 ways", func() {
+		It("should evict the oldest insertion and keep the set in insertion order regardless of reads", func() {
+			cache, err := NewCacheWithGeometry[string, int](1, 3, WithReplacementAlgo(FIFO_ALGO))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cache.Put("a", 1)
+			cache.Put("b", 2)
+			cache.Put("c", 3)
+			cache.Get("a")
+			cache.Put("a", 10)
+			cache.Put("d", 4)
+
+			Expect(cache.ListAll()).Should(Equal(map[string]int{"b": 2, "c": 3, "d": 4}))
+			keys := []string{}
+			for key := range cache.SetEntries(0) {
+				keys = append(keys, key)
+			}
+			Expect(keys).Should(Equal([]string{"d", "c", "b"}))
+		})
+
+		It("should not create a read buffer, since Get already takes the shared lock only", func() {
+			cache, err := NewCacheWithGeometry[string, int](1, 3, WithReplacementAlgo(FIFO_ALGO), WithReadBuffer(8))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cache.sets[0].reads).Should(BeNil())
+		})
+	})
+}
+
+func randomPolicyTest() {
+	Context("Given an empty policy", func() {
+		It("should not return a victim", func() {
+			_, ok := NewRandomPolicy[int]().Victim()
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("Given keys [1,2,3,4] inserted and key 2 removed", func() {
+		It("should choose the victim among the remaining keys with the provided generator", func() {
+			chosen := 0
+			policy := newRandomPolicy[int](func(n int) int {
+				Expect(n).Should(Equal(3))
+				return chosen
+			})
+			policy.OnInsert(1)
+			policy.OnInsert(2)
+			policy.OnInsert(3)
+			policy.OnInsert(4)
+			policy.OnAccess(3)
+			policy.OnRemove(2)
+
+			victims := []int{}
+			for chosen = 0; chosen < 3; chosen++ {
+				victim, ok := policy.Victim()
+				Expect(ok).Should(BeTrue())
+				victims = append(victims, victim)
+			}
+			Expect(victims).Should(ConsistOf(1, 3, 4))
+		})
+	})
+
+	Context("Given two Random caches with the same generator seed", func() {
+		It("should evict the same keys", func() {
+			newCache := func() *Cache[int, int] {
+				cache, err := NewCacheWithGeometry[int, int](2, 4, WithReplacementAlgo(RANDOM_ALGO), WithRand(rand.New(rand.NewPCG(1, 2))))
+				Expect(err).ShouldNot(HaveOccurred())
+				for i := 0; i < 100; i++ {
+					cache.Put(i, i)
+				}
+				return cache
+			}
+
+			first, second := newCache(), newCache()
+			Expect(first.Len()).Should(Equal(8))
+			Expect(first.ListAll()).Should(Equal(second.ListAll()))
+		})
+	})
+
+	Context("Given a Random cache used from several goroutines", func() {
+		It("should not return race condition errors if run test with -race flag", func() {
+			cache, err := NewCacheWithGeometry[int, int](4, 4, WithReplacementAlgo(RANDOM_ALGO), WithRand(rand.New(rand.NewPCG(1, 2))))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 200; i++ {
+						cache.Put(g*200+i, i)
+						cache.Get(g*200 + i/2)
+					}
+				}()
+			}
+			wg.Wait()
+			Expect(cache.Len()).Should(Equal(16))
+		})
+	})
+}
+
+// recordingPolicy is a custom EvictionPolicy that always evicts the first inserted key and records every hook
+type recordingPolicy struct {
+	keys  []string
+	calls []string
+}
+
+func (p *recordingPolicy) OnInsert(key string) {
+	p.keys = append(p.keys, key)
+	p.calls = append(p.calls, "insert:"+key)
+}
+
+func (p *recordingPolicy) OnAccess(key string) {
+	p.calls = append(p.calls, "access:"+key)
+}
+
+func (p *recordingPolicy) OnUpdate(key string) {
+	p.calls = append(p.calls, "update:"+key)
+}
+
+func (p *recordingPolicy) OnRemove(key string) {
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			break
+		}
+	}
+	p.calls = append(p.calls, "remove:"+key)
+}
+
+func (p *recordingPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+	return p.keys[0], true
+}
+
+// victimlessPolicy is a misbehaving custom EvictionPolicy whose victim is never a key of the set
+type victimlessPolicy struct {
+	victim string
+	found  bool
+}
+
+func (p *victimlessPolicy) OnInsert(key string) {}
+
+func (p *victimlessPolicy) OnAccess(key string) {}
+
+func (p *victimlessPolicy) OnUpdate(key string) {}
+
+func (p *victimlessPolicy) OnRemove(key string) {}
+
+func (p *victimlessPolicy) Victim() (string, bool) {
+	return p.victim, p.found
+}
+
+func customPolicyTest() {
+	Context("Given a custom policy defined through WithEvictionPolicy", func() {
+		It("should notify the policy about every change and evict the chosen victim", func() {
+			policy := new(recordingPolicy)
+			cache, err := NewCacheWithGeometry[string, int](1, 2, WithEvictionPolicy(func() EvictionPolicy[string] {
+				return policy
+			}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cache.Put("a", 1)
+			cache.Put("b", 2)
+			cache.Get("a")
+			cache.Put("b", 3)
+			cache.Put("c", 4)
+			cache.Delete("b")
+
+			Expect(policy.calls).Should(Equal([]string{
+				"insert:a",
+				"insert:b",
+				"access:a",
+				"update:b",
+				"remove:a",
+				"insert:c",
+				"remove:b",
+			}))
+			Expect(cache.ListAll()).Should(Equal(map[string]int{"c": 4}))
+		})
+	})
+
+	Context("Given a custom policy provided to NewCache", func() {
+		It("should evict the chosen victim", func() {
+			policies := []*recordingPolicy{}
+			cache, err := NewCache[string, int](1, PolicyConstructor[string](func() EvictionPolicy[string] {
+				policies = append(policies, new(recordingPolicy))
+				return policies[len(policies)-1]
+			}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cache.Put("a", 1)
+			cache.Put("b", 2)
+
+			Expect(policies).Should(HaveLen(1))
+			Expect(policies[0].calls).Should(Equal([]string{"insert:a", "remove:a", "insert:b"}))
+			Expect(cache.ListAll()).Should(Equal(map[string]int{"b": 2}))
+		})
+	})
+
+	Context("Given a custom policy without a victim of the set", func() {
+		It("should evict the entry at the back of the set instead of growing past its ways", func() {
+			for _, policy := range []*victimlessPolicy{{}, {victim: "unknown", found: true}} {
+				cache, err := NewCacheWithGeometry[string, int](1, 2, WithEvictionPolicy(func() EvictionPolicy[string] {
+					return policy
+				}))
+				Expect(err).ShouldNot(HaveOccurred())
+
+				for i, key := range []string{"a", "b", "c", "d"} {
+					cache.Put(key, i)
+				}
+
+				Expect(cache.Len()).Should(Equal(2))
+				Expect(cache.SetOccupancy()).Should(Equal([]int{2}))
+				Expect(cache.ListAll()).Should(Equal(map[string]int{"c": 2, "d": 3}))
+				Expect(cache.Stats().Evictions[EvictionReasonCapacity]).Should(BeEquivalentTo(2))
+			}
+		})
+	})
+
+	Context("Given a custom policy with a key type different from the cache key type", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[int, int](1, 2, WithEvictionPolicy(NewLRUPolicy[string]))).Error().Should(HaveOccurred())
+		})
+	})
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing option WithReadBuffer", readBufferTest)
+
+func readBufferTest() {
+	var cacheTester *Cache[string, int]
+
+	BeforeEach(func() {
+		var err error
+		// a single set with 2 ways, so every key competes for the same slots
+		cacheTester, err = NewCacheWithGeometry[string, int](1, 2, WithReadBuffer(4))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a hit", func() {
+		It("should return the value without reordering the set until the next write", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+
+			value, found := cacheTester.Get("a")
+			Expect(found).Should(BeTrue())
+			Expect(value).Should(Equal(1))
+			Expect(cacheTester.sets[0].items.Front().Value.(*entry[string, int]).key).Should(Equal("b"))
+			Expect(cacheTester.Stats().Hits).Should(BeEquivalentTo(1))
+
+			// the buffered read is applied before choosing the victim, so "b" is the least recently used key
+			cacheTester.Put("c", 3)
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"a": 1, "c": 3}))
+		})
+	})
+
+	Context("Given more hits than the buffer size", func() {
+		It("should apply the buffer once it's full", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+			for i := 0; i < 5; i++ {
+				cacheTester.Get("a")
+			}
+
+			Expect(cacheTester.sets[0].items.Front().Value.(*entry[string, int]).key).Should(Equal("a"))
+			Expect(pendingReads(&cacheTester.sets[0])).Should(BeNumerically("<", 4))
+		})
+	})
+
+	Context("Given a read of a key removed before the buffer is applied", func() {
+		It("should skip it", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Get("a")
+			cacheTester.Delete("a")
+			cacheTester.Put("a", 2)
+			cacheTester.Put("b", 3)
+
+			// the stale read of the first "a" must not promote the new one
+			Expect(cacheTester.sets[0].items.Back().Value.(*entry[string, int]).key).Should(Equal("a"))
+		})
+	})
+
+	Context("Given a miss", func() {
+		It("should report it", func() {
+			_, found := cacheTester.Get("missing")
+			Expect(found).Should(BeFalse())
+			Expect(cacheTester.Stats().Misses).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given a hit of an expired entry", func() {
+		It("should remove it and report a miss", func() {
+			clock := newFakeClock()
+			cacheTester, err := NewCacheWithGeometry[string, int](1, 2, WithReadBuffer(4), WithClock(clock.now))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cacheTester.PutWithTTL("a", 1, time.Second)
+			clock.advance(time.Second)
+
+			_, found := cacheTester.Get("a")
+			Expect(found).Should(BeFalse())
+			Expect(cacheTester.Stats().Entries).Should(BeZero())
+			Expect(cacheTester.Stats().Evictions[EvictionReasonExpired]).Should(BeEquivalentTo(1))
+		})
+	})
+
+	Context("Given several processors", func() {
+		BeforeEach(func() {
+			previous := runtime.GOMAXPROCS(8)
+			DeferCleanup(runtime.GOMAXPROCS, previous)
+		})
+
+		It("should split the buffer in one stripe per processor", func() {
+			stripes := newReadStripes(64)
+			Expect(stripes).Should(HaveLen(8))
+			for i := range stripes {
+				Expect(cap(stripes[i].reads)).Should(Equal(8))
+			}
+		})
+
+		It("should not create more stripes than entries", func() {
+			stripes := newReadStripes(2)
+			Expect(stripes).Should(HaveLen(2))
+			Expect(cap(stripes[0].reads)).Should(Equal(1))
+		})
+
+		It("should pad every stripe to a cache line", func() {
+			Expect(unsafe.Sizeof(readStripe{})).Should(BeEquivalentTo(cacheLineSize))
+		})
+
+		It("should drop a hit whose stripe is in use by another reader", func() {
+			cache, err := NewCacheWithGeometry[string, int](1, 2, WithReadBuffer(1))
+			Expect(err).ShouldNot(HaveOccurred())
+			cache.Put("a", 1)
+			for i := range cache.sets[0].reads {
+				cache.sets[0].reads[i].mutex.Lock()
+			}
+
+			_, found := cache.Get("a")
+			Expect(found).Should(BeTrue())
+			for i := range cache.sets[0].reads {
+				cache.sets[0].reads[i].mutex.Unlock()
+			}
+			Expect(pendingReads(&cache.sets[0])).Should(BeZero())
+		})
+	})
+
+	Context("Given a negative buffer size", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[string, int](1, 2, WithReadBuffer(-1))).Error().Should(HaveOccurred())
+		})
+	})
+
+	Context("Given a hit", func() {
+		It("should not allocate", func() {
+			cacheTester.Put("a", 1)
+			Expect(testing.AllocsPerRun(100, func() {
+				cacheTester.Get("a")
+			})).Should(BeZero())
+		})
+	})
+
+	Context("Given concurrent readers and writers", func() {
+		It("should not return race condition errors if run test with -race flag", func() {
+			cache, err := NewCacheWithGeometry[int, int](4, 4, WithReadBuffer(8))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var wg sync.WaitGroup
+			wg.Add(8)
+			for i := 0; i < 8; i++ {
+				go func(i int) {
+					defer wg.Done()
+					for key := 0; key < 200; key++ {
+						if i%4 == 0 {
+							cache.Put(key%32, key)
+						} else {
+							cache.Get(key % 32)
+						}
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			for i := range cache.sets {
+				Expect(cache.sets[i].len()).Should(BeNumerically("<=", 4))
+			}
+		})
+	})
+}
+
+// pendingReads returns the number of hits recorded in the read buffer of the set and not applied yet.
+func pendingReads[K comparable, V any](set *cacheSet[K, V]) int {
+	pending := 0
+	for i := range set.reads {
+		pending += len(set.reads[i].reads)
+	}
+	return pending
+}
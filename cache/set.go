@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"container/list"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// cacheSet is a single set of the cache. Every set has its own mutex, so operations on keys
+// mapped to different sets never block each other. A key always belongs to the same set,
+// so the entries of the set are indexed by key in the set itself instead of in a cache wide map.
+// items, entries and policy are created on the first insertion, so empty sets are cheap.
+// reads holds the stripes of the read buffer, only created if the cache was configured with WithReadBuffer.
+type cacheSet[K comparable, V any] struct {
+	mutex     sync.RWMutex
+	items     *list.List
+	entries   map[K]*list.Element
+	policy    EvictionPolicy[K]
+	evictions []eviction[K, V]
+	reads     []readStripe
+}
+
+// cacheLineSize is the usual size of a CPU cache line, used to pad the stripes of the read buffer.
+const cacheLineSize = 64
+
+// readStripe is a stripe of the read buffer of a set. Readers append to it while holding the shared lock
+// of the set and the mutex of the stripe, so readers of the same set only contend if they pick the same stripe.
+// It's applied while holding the exclusive lock of the set, when no reader can append to it.
+// It's padded to a cache line, so readers appending to different stripes don't invalidate each other's caches.
+type readStripe struct {
+	mutex sync.Mutex
+	reads []*list.Element
+	_     [cacheLineSize - unsafe.Sizeof(sync.Mutex{}) - unsafe.Sizeof([]*list.Element(nil))]byte
+}
+
+// newReadStripes returns the stripes of a read buffer of size entries: one stripe per processor,
+// rounded up to a power of two so a stripe is picked with a mask, but never more stripes than entries.
+func newReadStripes(size int) []readStripe {
+	count := 1
+	for count < runtime.GOMAXPROCS(0) && count*2 <= size {
+		count *= 2
+	}
+
+	stripes := make([]readStripe, count)
+	for i := range stripes {
+		stripes[i].reads = make([]*list.Element, 0, size/count)
+	}
+	return stripes
+}
+
+// init creates the set structures if they don't exist yet.
+// It must be invoked while holding the set mutex.
+func (s *cacheSet[K, V]) init(newPolicy func() EvictionPolicy[K]) {
+	if s.items != nil {
+		return
+	}
+	s.items = list.New()
+	s.entries = make(map[K]*list.Element)
+	s.policy = newPolicy()
+}
+
+// reset drops every entry of the set, so it's created again on the next insertion.
+// It must be invoked while holding the set mutex.
+func (s *cacheSet[K, V]) reset() {
+	s.items = nil
+	s.entries = nil
+	s.policy = nil
+}
+
+// recordRead records a hit of the provided element in a random stripe of the read buffer
+// and returns true if the stripe is full. The hit is dropped if another reader is using the stripe
+// or if the stripe is already full.
+// It must be invoked while holding the shared lock of the set.
+func (s *cacheSet[K, V]) recordRead(elem *list.Element) bool {
+	stripe := &s.reads[rand.Uint32()&uint32(len(s.reads)-1)]
+	if !stripe.mutex.TryLock() {
+		return false
+	}
+
+	if len(stripe.reads) < cap(stripe.reads) {
+		stripe.reads = append(stripe.reads, elem)
+	}
+	full := len(stripe.reads) == cap(stripe.reads)
+	stripe.mutex.Unlock()
+	return full
+}
+
+// applyReads moves the entries recorded in the read buffer to the front of the set and notifies the policy,
+// one stripe at a time and in the order they were read within a stripe. Entries removed after they were read are skipped.
+// It must be invoked while holding the set mutex.
+func (s *cacheSet[K, V]) applyReads() {
+	for i := range s.reads {
+		stripe := &s.reads[i]
+		for j, elem := range stripe.reads {
+			key := elem.Value.(*entry[K, V]).key
+			if current, found := s.entries[key]; found && current == elem {
+				s.items.MoveToFront(elem)
+				s.policy.OnAccess(key)
+			}
+			stripe.reads[j] = nil
+		}
+		stripe.reads = stripe.reads[:0]
+	}
+}
+
+// len returns the number of entries of the set.
+// It must be invoked while holding the set mutex.
+func (s *cacheSet[K, V]) len() int {
+	if s.items == nil {
+		return 0
+	}
+	return s.items.Len()
+}
+
+// setIndexOf returns the index of the set where the provided key is saved.
+// The hash is converted to an unsigned value first, since it can be negative on 32-bit platforms.
+func (c *Cache[K, V]) setIndexOf(key K) int {
+	return int(uint(c.hashKeyToIntConverter.hashKeyToInt(key)) % uint(c.setSize))
+}
+
+// setOf returns the index and the set where the provided key is saved.
+func (c *Cache[K, V]) setOf(key K) (int, *cacheSet[K, V]) {
+	setIndex := c.setIndexOf(key)
+	return setIndex, &c.sets[setIndex]
+}
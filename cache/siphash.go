@@ -0,0 +1,80 @@
+package cache
+
+import "math/bits"
+
+// sipHash is a streaming implementation of SipHash-2-4, a keyed hash function designed to resist
+// hash-flooding: without the key, it's not feasible to craft inputs that collide.
+// It works byte by byte, so its zero value lives on the stack and hashing doesn't allocate.
+type sipHash struct {
+	v0, v1, v2, v3 uint64
+	tail           uint64
+	tailLength     int
+	length         int
+}
+
+// newSipHash returns the initial state of SipHash-2-4 for the 128 bits key (k0, k1).
+func newSipHash(k0, k1 uint64) sipHash {
+	return sipHash{
+		v0: k0 ^ 0x736f6d6570736575,
+		v1: k1 ^ 0x646f72616e646f6d,
+		v2: k0 ^ 0x6c7967656e657261,
+		v3: k1 ^ 0x7465646279746573,
+	}
+}
+
+func (s *sipHash) writeByte(b byte) {
+	s.tail |= uint64(b) << (8 * s.tailLength)
+	s.tailLength++
+	s.length++
+	if s.tailLength == 8 {
+		s.compress(s.tail, 2)
+		s.tail = 0
+		s.tailLength = 0
+	}
+}
+
+func (s *sipHash) writeBytes(data []byte) {
+	for _, b := range data {
+		s.writeByte(b)
+	}
+}
+
+func (s *sipHash) writeString(data string) {
+	for i := 0; i < len(data); i++ {
+		s.writeByte(data[i])
+	}
+}
+
+// sum64 returns the hash of the data written so far.
+func (s *sipHash) sum64() uint64 {
+	s.compress(uint64(s.length&0xff)<<56|s.tail, 2)
+	s.v2 ^= 0xff
+	s.rounds(4)
+	return s.v0 ^ s.v1 ^ s.v2 ^ s.v3
+}
+
+// compress mixes a 64 bits block into the state.
+func (s *sipHash) compress(block uint64, rounds int) {
+	s.v3 ^= block
+	s.rounds(rounds)
+	s.v0 ^= block
+}
+
+func (s *sipHash) rounds(n int) {
+	for i := 0; i < n; i++ {
+		s.v0 += s.v1
+		s.v1 = bits.RotateLeft64(s.v1, 13)
+		s.v1 ^= s.v0
+		s.v0 = bits.RotateLeft64(s.v0, 32)
+		s.v2 += s.v3
+		s.v3 = bits.RotateLeft64(s.v3, 16)
+		s.v3 ^= s.v2
+		s.v0 += s.v3
+		s.v3 = bits.RotateLeft64(s.v3, 21)
+		s.v3 ^= s.v0
+		s.v2 += s.v1
+		s.v1 = bits.RotateLeft64(s.v1, 17)
+		s.v1 ^= s.v2
+		s.v2 = bits.RotateLeft64(s.v2, 32)
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import "sync/atomic"
+
+// Stats is a snapshot of the counters of a Cache, see Cache.Stats.
+type Stats struct {
+	// Hits is the number of Get invocations that found the key.
+	Hits uint64
+	// Misses is the number of Get invocations that didn't find the key, expired keys included.
+	Misses uint64
+	// Insertions is the number of new keys saved in the cache.
+	Insertions uint64
+	// Updates is the number of values overwritten for a key that was already cached.
+	Updates uint64
+	// Deletes is the number of keys removed with Delete.
+	Deletes uint64
+	// Evictions is the number of entries removed by the cache itself, per reason:
+	// EvictionReasonCapacity, EvictionReasonExpired and EvictionReasonCleared.
+	Evictions map[EvictionReason]uint64
+	// MissKinds classifies Misses per MissKind.
+	// It's only collected if the cache was created with WithMissClassification.
+	MissKinds map[MissKind]uint64
+	// Entries is the number of entries in the cache when the snapshot was taken.
+	Entries int
+}
+
+// HitRatio returns the ratio of hits over the total of Get invocations, or 0 if Get was never invoked.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// cacheStats holds the counters of a Cache. Its zero value is ready to use.
+// Hits and misses are only counted per set, see setCounters, so concurrent Get invocations
+// on different sets don't contend on the same counter.
+type cacheStats struct {
+	insertions atomic.Uint64
+	updates    atomic.Uint64
+	deletes    atomic.Uint64
+	evictions  [EvictionReasonCleared + 1]atomic.Uint64
+}
+
+// recordRemoval increases the counter matching the reason an entry left the cache.
+func (s *cacheStats) recordRemoval(reason EvictionReason) {
+	switch reason {
+	case EvictionReasonDeleted:
+		s.deletes.Add(1)
+	case EvictionReasonReplaced:
+		s.updates.Add(1)
+	default:
+		s.evictions[reason].Add(1)
+	}
+}
+
+// reset sets every counter back to zero.
+func (s *cacheStats) reset() {
+	s.insertions.Store(0)
+	s.updates.Store(0)
+	s.deletes.Store(0)
+	for i := range s.evictions {
+		s.evictions[i].Store(0)
+	}
+}
+
+// Stats returns a snapshot of the hit, miss, insertion, update, delete and eviction counters
+// collected since the cache was created or since the last ResetStats, and the current number of entries.
+func (c *Cache[K, V]) Stats() Stats {
+	var hits, misses uint64
+	missKinds := make(map[MissKind]uint64)
+	for _, setStats := range c.SetStats() {
+		hits += setStats.Hits
+		misses += setStats.Misses
+		if c.missClassifier != nil {
+			missKinds[CompulsoryMiss] += setStats.CompulsoryMisses
+			missKinds[CapacityMiss] += setStats.CapacityMisses
+			missKinds[ConflictMiss] += setStats.ConflictMisses
+		}
+	}
+
+	return Stats{
+		Hits:       hits,
+		Misses:     misses,
+		Insertions: c.stats.insertions.Load(),
+		Updates:    c.stats.updates.Load(),
+		Deletes:    c.stats.deletes.Load(),
+		Evictions: map[EvictionReason]uint64{
+			EvictionReasonCapacity: c.stats.evictions[EvictionReasonCapacity].Load(),
+			EvictionReasonExpired:  c.stats.evictions[EvictionReasonExpired].Load(),
+			EvictionReasonCleared:  c.stats.evictions[EvictionReasonCleared].Load(),
+		},
+		MissKinds: missKinds,
+		Entries:   c.Len(),
+	}
+}
+
+// ResetStats sets every counter back to zero, set counters included.
+// It's useful to measure the cache performance in time windows.
+func (c *Cache[K, V]) ResetStats() {
+	c.stats.reset()
+	for i := range c.setStats {
+		c.setStats[i].reset()
+	}
+}
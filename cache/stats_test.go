@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing function Stats", statsTest)
+
+func statsTest() {
+	var (
+		clock       *fakeClock
+		cacheTester *Cache[string, int]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		// a single set with 2 ways, so every key competes for the same slots
+		cacheTester, err = NewCacheWithGeometry[string, int](1, 2, WithClock(clock.now))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a new cache", func() {
+		It("should return zero counters", func() {
+			stats := cacheTester.Stats()
+			Expect(stats.Hits).Should(BeZero())
+			Expect(stats.Misses).Should(BeZero())
+			Expect(stats.Entries).Should(BeZero())
+			Expect(stats.HitRatio()).Should(BeZero())
+		})
+	})
+
+	Context("Given a mix of operations", func() {
+		It("should count every operation", func() {
+			cacheTester.Put("a", 1)                     // insertion
+			cacheTester.Put("a", 2)                     // update
+			cacheTester.Put("b", 3)                     // insertion
+			cacheTester.Put("c", 4)                     // insertion, "a" evicted by capacity
+			cacheTester.Get("b")                        // hit
+			cacheTester.Get("c")                        // hit
+			cacheTester.Get("a")                        // miss
+			cacheTester.Delete("b")                     // delete
+			cacheTester.Delete("missing")               // nothing to delete
+			cacheTester.PutWithTTL("d", 5, time.Second) // insertion
+			clock.advance(time.Second)
+			cacheTester.Get("d") // miss, "d" expired
+			cacheTester.Clear()  // "c" cleared
+
+			stats := cacheTester.Stats()
+			Expect(stats.Hits).Should(BeEquivalentTo(2))
+			Expect(stats.Misses).Should(BeEquivalentTo(2))
+			Expect(stats.Insertions).Should(BeEquivalentTo(4))
+			Expect(stats.Updates).Should(BeEquivalentTo(1))
+			Expect(stats.Deletes).Should(BeEquivalentTo(1))
+			Expect(stats.Evictions).Should(Equal(map[EvictionReason]uint64{
+				EvictionReasonCapacity: 1,
+				EvictionReasonExpired:  1,
+				EvictionReasonCleared:  1,
+			}))
+			Expect(stats.Entries).Should(BeZero())
+			Expect(stats.HitRatio()).Should(Equal(0.5))
+		})
+	})
+
+	Context("Given an expired key that is overwritten before being removed", func() {
+		It("should count an expired eviction and an insertion instead of an update", func() {
+			cacheTester.PutWithTTL("a", 1, time.Second)
+			clock.advance(2 * time.Second)
+			cacheTester.Put("a", 2)
+
+			stats := cacheTester.Stats()
+			Expect(stats.Insertions).Should(BeEquivalentTo(2))
+			Expect(stats.Updates).Should(BeZero())
+			Expect(stats.Evictions[EvictionReasonExpired]).Should(BeEquivalentTo(1))
+			Expect(stats.Entries).Should(Equal(1))
+		})
+	})
+
+	Context("Given ResetStats is invoked", func() {
+		It("should reset the counters but keep the number of entries", func() {
+			cacheTester.Put("a", 1)
+			cacheTester.Put("b", 2)
+			cacheTester.Put("c", 3)
+			cacheTester.Get("b")
+			cacheTester.Get("a")
+
+			cacheTester.ResetStats()
+
+			stats := cacheTester.Stats()
+			Expect(stats.Hits).Should(BeZero())
+			Expect(stats.Misses).Should(BeZero())
+			Expect(stats.Insertions).Should(BeZero())
+			Expect(stats.Evictions[EvictionReasonCapacity]).Should(BeZero())
+			Expect(stats.Entries).Should(Equal(2))
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+)
+
+// tagIndex maps every tag to the keys saved with it, so InvalidateTag only visits the entries of the tag.
+// It's shared by every set, so it has its own mutex, always acquired after the mutex of a set.
+// Its zero value is ready to use.
+type tagIndex[K comparable] struct {
+	mutex sync.Mutex
+	keys  map[string]map[K]struct{}
+}
+
+// add records that the provided key was saved with the provided tags.
+func (t *tagIndex[K]) add(key K, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.keys == nil {
+		t.keys = make(map[string]map[K]struct{})
+	}
+	for _, tag := range tags {
+		if t.keys[tag] == nil {
+			t.keys[tag] = make(map[K]struct{})
+		}
+		t.keys[tag][key] = struct{}{}
+	}
+}
+
+// remove drops the provided key from the provided tags, tags without keys are dropped too.
+func (t *tagIndex[K]) remove(key K, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, tag := range tags {
+		delete(t.keys[tag], key)
+		if len(t.keys[tag]) == 0 {
+			delete(t.keys, tag)
+		}
+	}
+}
+
+// reset drops every tag.
+func (t *tagIndex[K]) reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.keys = nil
+}
+
+// keysOf returns a copy of the keys saved with the provided tag.
+func (t *tagIndex[K]) keysOf(tag string) []K {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	keys := make([]K, 0, len(t.keys[tag]))
+	for key := range t.keys[tag] {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// PutWithTags works as Put, but it also associates the entry with the provided tags, e.g. the records
+// the value was built from, so it can be removed with InvalidateTag. Saving the key again with PutWithTags
+// replaces its tags, even with no tags, while Put, PutWithTTL and PutMany keep them.
+func (c *Cache[K, V]) PutWithTags(key K, value V, tags ...string) {
+	setIndex, set := c.setOf(key)
+	set.mutex.Lock()
+	defer c.unlock(set)
+
+	// never nil, since put keeps the previous tags of the key on nil
+	c.put(setIndex, key, value, c.expiresAt(c.defaultTTL), append(make([]string, 0, len(tags)), tags...))
+}
+
+// InvalidateTag removes every entry saved with the provided tag and returns how many were removed.
+// Removed entries are notified to the eviction listener with EvictionReasonDeleted.
+// It only visits the entries of the tag, locking every affected set once.
+func (c *Cache[K, V]) InvalidateTag(tag string) int {
+	keys := c.tags.keysOf(tag)
+	removed := 0
+	operations := c.groupBySet(len(keys), func(position int) K { return keys[position] })
+
+	c.forEachSet(operations, func(setIndex int, operations []setOperation) {
+		set := &c.sets[setIndex]
+		for _, operation := range operations {
+			key := keys[operation.position()]
+			// the key could have been saved again without the tag since the keys were copied
+			elem, found := set.entries[key]
+			if found && slices.Contains(elem.Value.(*entry[K, V]).tags, tag) {
+				c.remove(setIndex, key, EvictionReasonDeleted)
+				removed++
+			}
+		}
+	})
+	return removed
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing tags", func() {
+	Describe("testing function InvalidateTag", invalidateTagTest)
+	Describe("testing the tag index", tagIndexTest)
+})
+
+func invalidateTagTest() {
+	var (
+		evicted     map[string]EvictionReason
+		cacheTester *Cache[string, string]
+	)
+
+	BeforeEach(func() {
+		var err error
+		evicted = map[string]EvictionReason{}
+		cacheTester, err = NewCacheWithGeometry[string, string](4, 4, OnEvict(func(key string, value string, reason EvictionReason) {
+			evicted[key] = reason
+		}))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given entries saved with different tags", func() {
+		It("should only remove the entries of the invalidated tag", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.PutWithTags("friends:1", "<friends of 1>", "user:1", "user:2")
+			cacheTester.PutWithTags("profile:2", "<profile 2>", "user:2")
+			cacheTester.Put("home", "<home>")
+
+			Expect(cacheTester.InvalidateTag("user:1")).Should(Equal(2))
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]string{"profile:2": "<profile 2>", "home": "<home>"}))
+			Expect(evicted).Should(Equal(map[string]EvictionReason{
+				"profile:1": EvictionReasonDeleted,
+				"friends:1": EvictionReasonDeleted,
+			}))
+
+			Expect(cacheTester.InvalidateTag("user:2")).Should(Equal(1))
+			Expect(cacheTester.InvalidateTag("user:1")).Should(BeZero())
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]string{"home": "<home>"}))
+		})
+	})
+
+	Context("Given a tagged key saved again with other tags", func() {
+		It("should only be removed by the new tags", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.PutWithTags("profile:1", "<new profile 1>", "user:3")
+
+			Expect(cacheTester.InvalidateTag("user:1")).Should(BeZero())
+			Expect(cacheTester.InvalidateTag("user:3")).Should(Equal(1))
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+		})
+	})
+
+	Context("Given a tagged key saved again with Put", func() {
+		It("should keep its tags", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.Put("profile:1", "<new profile 1>")
+
+			Expect(cacheTester.InvalidateTag("user:1")).Should(Equal(1))
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+			Expect(evicted).Should(Equal(map[string]EvictionReason{
+				"profile:1": EvictionReasonDeleted,
+			}))
+		})
+	})
+
+	Context("Given a tagged key saved again with PutMany", func() {
+		It("should keep its tags", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.PutMany([]KeyValue[string, string]{{Key: "profile:1", Value: "<new profile 1>"}})
+
+			Expect(cacheTester.InvalidateTag("user:1")).Should(Equal(1))
+			Expect(cacheTester.ListAll()).Should(BeEmpty())
+		})
+	})
+
+	Context("Given a tagged key saved again with PutWithTags and no tags", func() {
+		It("should drop its tags", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.PutWithTags("profile:1", "<new profile 1>")
+
+			Expect(cacheTester.InvalidateTag("user:1")).Should(BeZero())
+			Expect(cacheTester.ListAll()).Should(HaveKey("profile:1"))
+		})
+	})
+
+	Context("Given an unknown tag", func() {
+		It("should not remove anything", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			Expect(cacheTester.InvalidateTag("unknown")).Should(BeZero())
+			Expect(cacheTester.Len()).Should(Equal(1))
+		})
+	})
+}
+
+func tagIndexTest() {
+	var cacheTester *Cache[string, string]
+
+	BeforeEach(func() {
+		var err error
+		// a single set with a single way, so every new key evicts the previous one
+		cacheTester, err = NewCacheWithGeometry[string, string](1, 1)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given a tagged entry evicted by capacity", func() {
+		It("should drop it from the index", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1", "user:2")
+			cacheTester.Put("home", "<home>")
+
+			Expect(cacheTester.tags.keys).Should(BeEmpty())
+		})
+	})
+
+	Context("Given a tagged entry removed with Delete", func() {
+		It("should drop it from the index", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.Delete("profile:1")
+
+			Expect(cacheTester.tags.keys).Should(BeEmpty())
+		})
+	})
+
+	Context("Given Clear is invoked", func() {
+		It("should drop every entry from the index", func() {
+			cacheTester.PutWithTags("profile:1", "<profile 1>", "user:1")
+			cacheTester.Clear()
+
+			Expect(cacheTester.tags.keys).Should(BeEmpty())
+		})
+	})
+
+	Context("Given the tags slice is changed after the entry is saved", func() {
+		It("should keep the original tags", func() {
+			tags := []string{"user:1"}
+			cacheTester.PutWithTags("profile:1", "<profile 1>", tags...)
+			tags[0] = "user:2"
+
+			Expect(cacheTester.tags.keys).Should(Equal(map[string]map[string]struct{}{
+				"user:1": {"profile:1": {}},
+			}))
+		})
+	})
+}
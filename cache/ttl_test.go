@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("testing expiration", func() {
+	Describe("testing function PutWithTTL", putWithTTLTest)
+	Describe("testing option WithDefaultTTL", defaultTTLTest)
+})
+
+// fakeClock is a manually advanced clock used to test expirations
+type fakeClock struct {
+	current time.Time
+	mutex   sync.Mutex
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{current: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.current
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.current = f.current.Add(d)
+}
+
+// newModuloCacheTester returns a cache with numSets sets of ways entries each, where every int key is saved
+// in the set key % numSets, its fake clock and a pointer to the number of keys hashed so far
+func newModuloCacheTester(numSets, ways int, opts ...Option) (*Cache[int, string], *fakeClock, *int) {
+	clock := newFakeClock()
+	hashes := new(int)
+	opts = append(opts, WithClock(clock.now), WithHasher(HasherFunc[int](func(key int) uint64 {
+		*hashes++
+		return uint64(key)
+	})))
+	cacheTester, err := NewCacheWithGeometry[int, string](numSets, ways, opts...)
+	Expect(err).ShouldNot(HaveOccurred())
+	return cacheTester, clock, hashes
+}
+
+func putWithTTLTest() {
+	var (
+		clock       *fakeClock
+		cacheTester *Cache[string, int]
+	)
+
+	BeforeEach(func() {
+		var err error
+		clock = newFakeClock()
+		cacheTester, err = NewCacheWithGeometry[string, int](4, 4, WithClock(clock.now))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("Given an entry saved with a ttl of 1 minute", func() {
+		It("should be returned before the ttl elapses", func() {
+			cacheTester.PutWithTTL("foo", 1, time.Minute)
+			clock.advance(59 * time.Second)
+
+			value, found := cacheTester.Get("foo")
+			Expect(found).Should(BeTrue())
+			Expect(value).Should(Equal(1))
+		})
+
+		It("should be treated as a miss and removed once the ttl elapses", func() {
+			cacheTester.PutWithTTL("foo", 1, time.Minute)
+			clock.advance(time.Minute)
+
+			_, found := cacheTester.Get("foo")
+			Expect(found).Should(BeFalse())
+			Expect(cacheTester.ListAll()).ShouldNot(HaveKey("foo"))
+		})
+
+		It("should not be listed by ListAll once the ttl elapses", func() {
+			cacheTester.PutWithTTL("foo", 1, time.Minute)
+			cacheTester.PutWithTTL("bar", 2, time.Hour)
+			cacheTester.Put("baz", 3)
+			clock.advance(2 * time.Minute)
+
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"bar": 2, "baz": 3}))
+		})
+
+		It("should refresh the expiration when the key is overwritten", func() {
+			cacheTester.PutWithTTL("foo", 1, time.Minute)
+			clock.advance(45 * time.Second)
+			cacheTester.PutWithTTL("foo", 2, time.Minute)
+			clock.advance(45 * time.Second)
+
+			value, found := cacheTester.Get("foo")
+			Expect(found).Should(BeTrue())
+			Expect(value).Should(Equal(2))
+		})
+	})
+
+	Context("Given an entry saved with a ttl <= 0", func() {
+		It("should never expire", func() {
+			cacheTester.PutWithTTL("foo", 1, 0)
+			cacheTester.PutWithTTL("bar", 2, -time.Second)
+			clock.advance(24 * time.Hour)
+
+			Expect(cacheTester.ListAll()).Should(Equal(map[string]int{"foo": 1, "bar": 2}))
+		})
+	})
+}
+
+func defaultTTLTest() {
+	Context("Given a default ttl of 1 minute", func() {
+		It("should expire entries saved with Put", func() {
+			clock := newFakeClock()
+			cacheTester, err := NewCacheWithGeometry[string, int](4, 4, WithClock(clock.now), WithDefaultTTL(time.Minute))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			cacheTester.Put("foo", 1)
+			cacheTester.PutWithTTL("bar", 2, time.Hour)
+			clock.advance(time.Minute)
+
+			_, found := cacheTester.Get("foo")
+			Expect(found).Should(BeFalse())
+			_, found = cacheTester.Get("bar")
+			Expect(found).Should(BeTrue())
+		})
+	})
+
+	Context("Given a negative default ttl", func() {
+		It("should return an error", func() {
+			Expect(NewCacheWithGeometry[string, int](4, 4, WithDefaultTTL(-time.Minute))).Error().Should(HaveOccurred())
+		})
+	})
+}